@@ -1,8 +1,18 @@
+// Package config loads the service configuration. Every setting is resolved
+// from, in order of precedence:
+//
+//  1. command-line flags, e.g. --db-host
+//  2. environment variables, e.g. DB_HOST, including those from .env
+//  3. the YAML or TOML file given by --config or CONFIG_FILE, e.g. db.host
+//  4. the defaults in settings
+//
+// See config.example.yaml for all settings.
 package config
 
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -10,9 +20,35 @@ import (
 type ConfigProvider interface {
 	GetHTTPPort() string
 	GetHTTPHost() string
+	GetHTTPReadHeaderTimeoutSeconds() int
+	GetHTTPReadTimeoutSeconds() int
+	// GetHTTPWriteTimeoutSeconds is 0 for no limit, which category exports
+	// need as they stream for as long as the catalog takes.
+	GetHTTPWriteTimeoutSeconds() int
+	GetHTTPIdleTimeoutSeconds() int
+	// GetShutdownDrainSeconds is how long /readyz reports not-ready before
+	// the servers stop, giving load balancers time to stop routing to us.
+	GetShutdownDrainSeconds() int
+	// GetShutdownTimeoutSeconds bounds how long in-flight requests and
+	// background workers may take to finish on shutdown.
+	GetShutdownTimeoutSeconds() int
+
+	GetGRPCHost() string
+	GetGRPCPort() string
+	GetGRPCMaxRecvMessageBytes() int
+	GetGRPCMaxSendMessageBytes() int
+	// GetGRPCKeepaliveSeconds is the idle time after which the server pings
+	// a client, which is dropped if it does not answer within
+	// GetGRPCKeepaliveTimeoutSeconds.
+	GetGRPCKeepaliveSeconds() int
+	GetGRPCKeepaliveTimeoutSeconds() int
 
 	GetBookGRPCHost() string
 	GetBookGRPCPort() string
+	GetBookGRPCCallTimeoutSeconds() int
+	// GetBookGRPCKeepaliveSeconds is the idle time after which the connection
+	// to the Book service is pinged; 0 disables pings.
+	GetBookGRPCKeepaliveSeconds() int
 
 	GetDBHost() string
 	GetDBPort() string
@@ -20,66 +56,326 @@ type ConfigProvider interface {
 	GetDBPassword() string
 	GetDBName() string
 	GetSSLMode() string
+	// GetDBMaxOpenConns is 0 for no limit.
+	GetDBMaxOpenConns() int
+	GetDBMaxIdleConns() int
+	// GetDBConnMaxLifetimeMinutes is 0 to keep connections forever.
+	GetDBConnMaxLifetimeMinutes() int
 
 	GetBasicAuthUsername() string
 	GetBasicAuthPassword() string
+	// GetMetricsBasicAuth puts /metrics behind the admin basic auth
+	// credentials; otherwise it is public.
+	GetMetricsBasicAuth() bool
+
+	// GetLogFormat is "json" for structured logs or "text".
+	GetLogFormat() string
+	// GetLogLevel is a logrus level such as "info" or "debug".
+	GetLogLevel() string
+
+	// GetTracingExporter is "none", "stdout" or "otlp".
+	GetTracingExporter() string
+	// GetTracingOTLPEndpoint is the host:port of the OTLP/gRPC collector.
+	GetTracingOTLPEndpoint() string
+	GetTracingOTLPInsecure() bool
+	// GetTracingSampleRatio is the fraction of new traces recorded.
+	GetTracingSampleRatio() float64
+
+	// GetJWKSSource is the file path or http(s) URL of the JWKS tokens are
+	// verified against. When empty, the PEM public key is used instead.
+	GetJWKSSource() string
+	GetJWKSRefreshMinutes() int
+	GetJWTPublicKeyPath() string
+	// GetJWTPrivateKeyPath is only needed to sign tokens, e.g. with the
+	// "token" subcommand in local development.
+	GetJWTPrivateKeyPath() string
+	GetJWTSigningKeyID() string
+	// GetJWTIssuers lists the accepted "iss" claims; empty accepts any issuer.
+	GetJWTIssuers() []string
+	// GetJWTAudiences lists the accepted "aud" claims; empty accepts any
+	// audience.
+	GetJWTAudiences() []string
+	GetJWTAlgorithms() []string
+	// GetJWTLeewaySeconds is the clock skew tolerated when checking the
+	// expiry and not-before times of tokens.
+	GetJWTLeewaySeconds() int
+	// GetRevocationRefreshSeconds is how often the token revocation list is
+	// reloaded from the database, bounding how long revocations made by other
+	// instances take to apply.
+	GetRevocationRefreshSeconds() int
+
+	// GetCategoryRetentionDays is how many days soft-deleted categories are
+	// kept before they are purged; 0 disables purging.
+	GetCategoryRetentionDays() int
+	// GetOutboxRetentionDays is how many days delivered outbox events are
+	// kept; 0 keeps them.
+	GetOutboxRetentionDays() int
+	// GetIdempotencyKeyTTLHours is how long responses to requests with an
+	// Idempotency-Key are kept for replay.
+	GetIdempotencyKeyTTLHours() int
+	// GetIdempotencyKeyLeaseSeconds is how long a request with an
+	// Idempotency-Key may take before a retry can take the key over.
+	GetIdempotencyKeyLeaseSeconds() int
+
+	// GetRolePermissions maps token roles to the permissions they grant. The
+	// role "*" applies to every authenticated user.
+	GetRolePermissions() map[string][]string
 }
 
-type EnvConfig struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	SSLMode    string
+type Config struct {
+	DBHost                   string
+	DBPort                   string
+	DBUser                   string
+	DBPassword               string
+	DBName                   string
+	SSLMode                  string
+	DBMaxOpenConns           int
+	DBMaxIdleConns           int
+	DBConnMaxLifetimeMinutes int
+
+	HTTPHost                     string
+	HTTPPort                     string
+	HTTPReadHeaderTimeoutSeconds int
+	HTTPReadTimeoutSeconds       int
+	HTTPWriteTimeoutSeconds      int
+	HTTPIdleTimeoutSeconds       int
 
-	HTTPHost string
-	HTTPPort string
+	ShutdownDrainSeconds   int
+	ShutdownTimeoutSeconds int
 
-	BookGRPCHost string
-	BookGRPCPort string
+	GRPCHost                    string
+	GRPCPort                    string
+	GRPCMaxRecvMessageBytes     int
+	GRPCMaxSendMessageBytes     int
+	GRPCKeepaliveSeconds        int
+	GRPCKeepaliveTimeoutSeconds int
+
+	BookGRPCHost               string
+	BookGRPCPort               string
+	BookGRPCCallTimeoutSeconds int
+	BookGRPCKeepaliveSeconds   int
 
 	BasicAuthUsername string
 	BasicAuthPassword string
+	MetricsBasicAuth  bool
+
+	LogFormat string
+	LogLevel  string
+
+	TracingExporter     string
+	TracingOTLPEndpoint string
+	TracingOTLPInsecure bool
+	TracingSampleRatio  float64
+
+	JWKSSource         string
+	JWKSRefreshMinutes int
+	JWTPublicKeyPath   string
+	JWTPrivateKeyPath  string
+	JWTSigningKeyID    string
+	JWTIssuers         []string
+	JWTAudiences       []string
+	JWTAlgorithms      []string
+	JWTLeewaySeconds   int
+
+	RevocationRefreshSeconds int
+
+	CategoryRetentionDays      int
+	OutboxRetentionDays        int
+	IdempotencyKeyTTLHours     int
+	IdempotencyKeyLeaseSeconds int
+
+	RolePermissions map[string][]string
 }
 
-func (e *EnvConfig) GetHTTPHost() string { return e.HTTPHost }
-func (e *EnvConfig) GetHTTPPort() string { return e.HTTPPort }
+func (c *Config) GetHTTPHost() string                  { return c.HTTPHost }
+func (c *Config) GetHTTPPort() string                  { return c.HTTPPort }
+func (c *Config) GetHTTPReadHeaderTimeoutSeconds() int { return c.HTTPReadHeaderTimeoutSeconds }
+func (c *Config) GetHTTPReadTimeoutSeconds() int       { return c.HTTPReadTimeoutSeconds }
+func (c *Config) GetHTTPWriteTimeoutSeconds() int      { return c.HTTPWriteTimeoutSeconds }
+func (c *Config) GetHTTPIdleTimeoutSeconds() int       { return c.HTTPIdleTimeoutSeconds }
+
+func (c *Config) GetShutdownDrainSeconds() int   { return c.ShutdownDrainSeconds }
+func (c *Config) GetShutdownTimeoutSeconds() int { return c.ShutdownTimeoutSeconds }
+
+func (c *Config) GetGRPCHost() string                 { return c.GRPCHost }
+func (c *Config) GetGRPCPort() string                 { return c.GRPCPort }
+func (c *Config) GetGRPCMaxRecvMessageBytes() int     { return c.GRPCMaxRecvMessageBytes }
+func (c *Config) GetGRPCMaxSendMessageBytes() int     { return c.GRPCMaxSendMessageBytes }
+func (c *Config) GetGRPCKeepaliveSeconds() int        { return c.GRPCKeepaliveSeconds }
+func (c *Config) GetGRPCKeepaliveTimeoutSeconds() int { return c.GRPCKeepaliveTimeoutSeconds }
+
+func (c *Config) GetBookGRPCHost() string            { return c.BookGRPCHost }
+func (c *Config) GetBookGRPCPort() string            { return c.BookGRPCPort }
+func (c *Config) GetBookGRPCCallTimeoutSeconds() int { return c.BookGRPCCallTimeoutSeconds }
+func (c *Config) GetBookGRPCKeepaliveSeconds() int   { return c.BookGRPCKeepaliveSeconds }
+
+func (c *Config) GetDBHost() string                { return c.DBHost }
+func (c *Config) GetDBPort() string                { return c.DBPort }
+func (c *Config) GetDBUser() string                { return c.DBUser }
+func (c *Config) GetDBPassword() string            { return c.DBPassword }
+func (c *Config) GetDBName() string                { return c.DBName }
+func (c *Config) GetSSLMode() string               { return c.SSLMode }
+func (c *Config) GetDBMaxOpenConns() int           { return c.DBMaxOpenConns }
+func (c *Config) GetDBMaxIdleConns() int           { return c.DBMaxIdleConns }
+func (c *Config) GetDBConnMaxLifetimeMinutes() int { return c.DBConnMaxLifetimeMinutes }
+
+func (c *Config) GetBasicAuthUsername() string { return c.BasicAuthUsername }
+func (c *Config) GetBasicAuthPassword() string { return c.BasicAuthPassword }
+func (c *Config) GetMetricsBasicAuth() bool    { return c.MetricsBasicAuth }
+
+func (c *Config) GetLogFormat() string { return c.LogFormat }
+func (c *Config) GetLogLevel() string  { return c.LogLevel }
 
-func (e *EnvConfig) GetBookGRPCHost() string { return e.BookGRPCHost }
-func (e *EnvConfig) GetBookGRPCPort() string { return e.BookGRPCPort }
+func (c *Config) GetTracingExporter() string     { return c.TracingExporter }
+func (c *Config) GetTracingOTLPEndpoint() string { return c.TracingOTLPEndpoint }
+func (c *Config) GetTracingOTLPInsecure() bool   { return c.TracingOTLPInsecure }
+func (c *Config) GetTracingSampleRatio() float64 { return c.TracingSampleRatio }
 
-func (e *EnvConfig) GetDBHost() string     { return e.DBHost }
-func (e *EnvConfig) GetDBPort() string     { return e.DBPort }
-func (e *EnvConfig) GetDBUser() string     { return e.DBUser }
-func (e *EnvConfig) GetDBPassword() string { return e.DBPassword }
-func (e *EnvConfig) GetDBName() string     { return e.DBName }
-func (e *EnvConfig) GetSSLMode() string    { return e.SSLMode }
+func (c *Config) GetJWKSSource() string        { return c.JWKSSource }
+func (c *Config) GetJWKSRefreshMinutes() int   { return c.JWKSRefreshMinutes }
+func (c *Config) GetJWTPublicKeyPath() string  { return c.JWTPublicKeyPath }
+func (c *Config) GetJWTPrivateKeyPath() string { return c.JWTPrivateKeyPath }
+func (c *Config) GetJWTSigningKeyID() string   { return c.JWTSigningKeyID }
+func (c *Config) GetJWTIssuers() []string      { return c.JWTIssuers }
+func (c *Config) GetJWTAudiences() []string    { return c.JWTAudiences }
+func (c *Config) GetJWTAlgorithms() []string   { return c.JWTAlgorithms }
+func (c *Config) GetJWTLeewaySeconds() int     { return c.JWTLeewaySeconds }
 
-func (e *EnvConfig) GetBasicAuthUsername() string { return e.BasicAuthUsername }
-func (e *EnvConfig) GetBasicAuthPassword() string { return e.BasicAuthPassword }
+func (c *Config) GetRevocationRefreshSeconds() int { return c.RevocationRefreshSeconds }
+
+func (c *Config) GetCategoryRetentionDays() int      { return c.CategoryRetentionDays }
+func (c *Config) GetOutboxRetentionDays() int        { return c.OutboxRetentionDays }
+func (c *Config) GetIdempotencyKeyTTLHours() int     { return c.IdempotencyKeyTTLHours }
+func (c *Config) GetIdempotencyKeyLeaseSeconds() int { return c.IdempotencyKeyLeaseSeconds }
+
+func (c *Config) GetRolePermissions() map[string][]string { return c.RolePermissions }
+
+// Errors lists every problem found in the configuration.
+type Errors []error
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, err := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
 
-func LoadConfig() ConfigProvider {
+// LoadConfig loads the configuration of the server from args, the
+// environment and the config file, and validates it. The error lists every
+// missing or invalid value; it is flag.ErrHelp if args asked for usage.
+func LoadConfig(args []string) (ConfigProvider, error) {
+	cfg, problems, err := load(args)
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return cfg, nil
+}
+
+// Load loads the configuration like LoadConfig, but only checks that values
+// can be parsed. It suits commands that need a few settings only.
+func Load(args []string) (*Config, error) {
+	cfg, problems, err := load(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return cfg, nil
+}
+
+func load(args []string) (*Config, Errors, error) {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("Gagal membaca file .env, menggunakan environment variables yang tersedia")
 	}
 
-	return &EnvConfig{
-		HTTPHost: os.Getenv("HTTP_HOST"),
-		HTTPPort: os.Getenv("HTTP_PORT"),
+	l := newLoader()
+	configFile, err := l.parseFlags(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
+	}
+	if configFile != "" {
+		if err := l.readFile(configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cfg := &Config{
+		HTTPHost:                     l.str("HTTP_HOST"),
+		HTTPPort:                     l.str("HTTP_PORT"),
+		HTTPReadHeaderTimeoutSeconds: l.integer("HTTP_READ_HEADER_TIMEOUT_SECONDS"),
+		HTTPReadTimeoutSeconds:       l.integer("HTTP_READ_TIMEOUT_SECONDS"),
+		HTTPWriteTimeoutSeconds:      l.integer("HTTP_WRITE_TIMEOUT_SECONDS"),
+		HTTPIdleTimeoutSeconds:       l.integer("HTTP_IDLE_TIMEOUT_SECONDS"),
+
+		ShutdownDrainSeconds:   l.integer("SHUTDOWN_DRAIN_SECONDS"),
+		ShutdownTimeoutSeconds: l.integer("SHUTDOWN_TIMEOUT_SECONDS"),
+
+		GRPCHost:                    l.str("GRPC_HOST"),
+		GRPCPort:                    l.str("GRPC_PORT"),
+		GRPCMaxRecvMessageBytes:     l.integer("GRPC_MAX_RECV_MESSAGE_BYTES"),
+		GRPCMaxSendMessageBytes:     l.integer("GRPC_MAX_SEND_MESSAGE_BYTES"),
+		GRPCKeepaliveSeconds:        l.integer("GRPC_KEEPALIVE_SECONDS"),
+		GRPCKeepaliveTimeoutSeconds: l.integer("GRPC_KEEPALIVE_TIMEOUT_SECONDS"),
+
+		BookGRPCHost:               l.str("BOOK_GRPC_HOST"),
+		BookGRPCPort:               l.str("BOOK_GRPC_PORT"),
+		BookGRPCCallTimeoutSeconds: l.integer("BOOK_GRPC_CALL_TIMEOUT_SECONDS"),
+		BookGRPCKeepaliveSeconds:   l.integer("BOOK_GRPC_KEEPALIVE_SECONDS"),
 
-		BookGRPCHost: os.Getenv("BOOK_GRPC_HOST"),
-		BookGRPCPort: os.Getenv("BOOK_GRPC_PORT"),
+		DBHost:                   l.str("DB_HOST"),
+		DBPort:                   l.str("DB_PORT"),
+		DBUser:                   l.str("DB_USER"),
+		DBPassword:               l.lookup("DB_PASSWORD"),
+		DBName:                   l.str("DB_NAME"),
+		SSLMode:                  l.str("DB_SSLMODE"),
+		DBMaxOpenConns:           l.integer("DB_MAX_OPEN_CONNS"),
+		DBMaxIdleConns:           l.integer("DB_MAX_IDLE_CONNS"),
+		DBConnMaxLifetimeMinutes: l.integer("DB_CONN_MAX_LIFETIME_MINUTES"),
 
-		DBHost:     os.Getenv("DB_HOST"),
-		DBPort:     os.Getenv("DB_PORT"),
-		DBUser:     os.Getenv("DB_USER"),
-		DBPassword: os.Getenv("DB_PASSWORD"),
-		DBName:     os.Getenv("DB_NAME"),
-		SSLMode:    os.Getenv("DB_SSLMODE"),
+		BasicAuthUsername: l.str("BASIC_AUTH_USER"),
+		BasicAuthPassword: l.lookup("BASIC_AUTH_PASS"),
+		MetricsBasicAuth:  l.boolean("METRICS_BASIC_AUTH"),
 
-		BasicAuthUsername: os.Getenv("BASIC_AUTH_USER"),
-		BasicAuthPassword: os.Getenv("BASIC_AUTH_PASS"),
+		LogFormat: l.str("LOG_FORMAT"),
+		LogLevel:  l.str("LOG_LEVEL"),
+
+		TracingExporter:     l.str("TRACING_EXPORTER"),
+		TracingOTLPEndpoint: l.str("TRACING_OTLP_ENDPOINT"),
+		TracingOTLPInsecure: l.boolean("TRACING_OTLP_INSECURE"),
+		TracingSampleRatio:  l.ratio("TRACING_SAMPLE_RATIO"),
+
+		JWKSSource:         l.str("JWKS_SOURCE"),
+		JWKSRefreshMinutes: l.integer("JWKS_REFRESH_MINUTES"),
+		JWTPublicKeyPath:   l.str("JWT_PUBLIC_KEY_PATH"),
+		JWTPrivateKeyPath:  l.str("JWT_PRIVATE_KEY_PATH"),
+		JWTSigningKeyID:    l.str("JWT_SIGNING_KEY_ID"),
+		JWTIssuers:         l.list("JWT_ISSUERS"),
+		JWTAudiences:       l.list("JWT_AUDIENCES"),
+		JWTAlgorithms:      l.list("JWT_ALGORITHMS"),
+		JWTLeewaySeconds:   l.integer("JWT_LEEWAY_SECONDS"),
+
+		RevocationRefreshSeconds: l.integer("REVOCATION_REFRESH_SECONDS"),
+
+		CategoryRetentionDays:      l.integer("CATEGORY_RETENTION_DAYS"),
+		OutboxRetentionDays:        l.integer("OUTBOX_RETENTION_DAYS"),
+		IdempotencyKeyTTLHours:     l.integer("IDEMPOTENCY_KEY_TTL_HOURS"),
+		IdempotencyKeyLeaseSeconds: l.integer("IDEMPOTENCY_KEY_LEASE_SECONDS"),
+
+		RolePermissions: l.rolePermissions("ROLE_PERMISSIONS"),
 	}
+
+	return cfg, l.problems, nil
 }
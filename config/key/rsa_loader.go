@@ -8,23 +8,8 @@ import (
 	"os"
 )
 
-// LoadRSAKeys reads private and public keys from files
-func LoadRSAKeys(privateKeyPath, publicKeyPath string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	privateKey, err := loadRSAPrivateKey(privateKeyPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	publicKey, err := loadRSAPublicKey(publicKeyPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return privateKey, publicKey, nil
-}
-
-// loadRSAPrivateKey reads the private key from a file
-func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+// LoadRSAPrivateKey reads the private key from a file
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
 	keyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -48,8 +33,8 @@ func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
-// loadRSAPublicKey reads the public key from a file
-func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+// LoadRSAPublicKey reads the public key from a file
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
 	keyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// loader resolves settings from their layers and collects every value that
+// cannot be parsed, so that all of them are reported at once.
+type loader struct {
+	flags    map[string]string
+	file     map[string]string
+	defaults map[string]string
+	problems Errors
+}
+
+func newLoader() *loader {
+	defaults := make(map[string]string, len(settings))
+	for _, s := range settings {
+		defaults[s.key] = s.value
+	}
+	return &loader{flags: map[string]string{}, file: map[string]string{}, defaults: defaults}
+}
+
+// parseFlags reads the settings given on the command line and returns the
+// config file named by --config.
+func (l *loader) parseFlags(args []string) (string, error) {
+	flags := flag.NewFlagSet("category-service", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML or TOML config file (env CONFIG_FILE)")
+	values := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		usage := s.usage + " (env " + s.key + ")"
+		values[s.key] = flags.String(flagName(s.key), s.value, usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() > 0 {
+		return "", fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	// Only flags given explicitly override the other layers.
+	flags.Visit(func(f *flag.Flag) {
+		for key, value := range values {
+			if flagName(key) == f.Name {
+				l.flags[key] = *value
+			}
+		}
+	})
+	return *configFile, nil
+}
+
+// readFile loads a YAML or TOML config file, chosen by its extension.
+// Unknown keys are reported as problems.
+func (l *loader) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	tree := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	l.flatten("", tree)
+	return nil
+}
+
+// flatten stores the leaves of tree under their keys joined with
+// underscores, so that db.host becomes DB_HOST.
+func (l *loader) flatten(prefix string, tree map[string]interface{}) {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := tree[name]
+		key := strings.ToUpper(prefix + name)
+		if _, known := l.defaults[key]; !known {
+			if subtree, ok := value.(map[string]interface{}); ok {
+				l.flatten(key+"_", subtree)
+				continue
+			}
+			l.problems = append(l.problems, fmt.Errorf("config file: unknown setting %s", strings.ToLower(key)))
+			continue
+		}
+		l.file[key] = formatFileValue(value)
+	}
+}
+
+// formatFileValue converts a config file value to the format of the
+// environment variable: lists are comma-separated and maps, used for
+// ROLE_PERMISSIONS, become "key=a,b;key=c".
+func formatFileValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatFileValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]string, 0, len(keys))
+		for _, key := range keys {
+			entries = append(entries, key+"="+formatFileValue(v[key]))
+		}
+		return strings.Join(entries, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// lookup returns the value of key from, in order of precedence, the command
+// line, the environment (including .env), the config file and the defaults.
+// Empty environment variables count as unset.
+func (l *loader) lookup(key string) string {
+	if value, ok := l.flags[key]; ok {
+		return value
+	}
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if value, ok := l.file[key]; ok {
+		return value
+	}
+	return l.defaults[key]
+}
+
+func (l *loader) str(key string) string {
+	return strings.TrimSpace(l.lookup(key))
+}
+
+// integer reads a non-negative integer.
+func (l *loader) integer(key string) int {
+	raw := l.str(key)
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		l.problems = append(l.problems, fmt.Errorf("%s must be a non-negative integer, got %q", key, raw))
+		return 0
+	}
+	return value
+}
+
+func (l *loader) boolean(key string) bool {
+	raw := l.str(key)
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Errorf("%s must be true or false, got %q", key, raw))
+		return false
+	}
+	return value
+}
+
+// ratio reads a number between 0 and 1.
+func (l *loader) ratio(key string) float64 {
+	raw := l.str(key)
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || value > 1 {
+		l.problems = append(l.problems, fmt.Errorf("%s must be a number between 0 and 1, got %q", key, raw))
+		return 0
+	}
+	return value
+}
+
+// list reads a comma-separated list, skipping empty entries.
+func (l *loader) list(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(l.str(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// rolePermissions reads "role=perm,perm;role=perm" into a map.
+func (l *loader) rolePermissions(key string) map[string][]string {
+	rolePermissions := map[string][]string{}
+	for _, entry := range strings.Split(l.str(key), ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		role, permissions, found := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+		if !found || role == "" {
+			l.problems = append(l.problems, fmt.Errorf(`%s entry %q must look like "role=permission,permission"`, key, entry))
+			continue
+		}
+		for _, permission := range strings.Split(permissions, ",") {
+			if permission = strings.TrimSpace(permission); permission != "" {
+				rolePermissions[role] = append(rolePermissions[role], permission)
+			}
+		}
+	}
+	return rolePermissions
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := writeConfigFile(t, "config.yaml", "db:\n  host: file-host\n  max_open_conns: 40\n")
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           map[string]string
+		wantHost      string
+		wantOpenConns int
+	}{
+		{
+			name:          "defaults",
+			wantHost:      "",
+			wantOpenConns: 25,
+		},
+		{
+			name:          "file overrides defaults",
+			args:          []string{"--config", yamlFile},
+			wantHost:      "file-host",
+			wantOpenConns: 40,
+		},
+		{
+			name:          "file named by CONFIG_FILE",
+			env:           map[string]string{"CONFIG_FILE": yamlFile},
+			wantHost:      "file-host",
+			wantOpenConns: 40,
+		},
+		{
+			name:          "environment overrides file",
+			args:          []string{"--config", yamlFile},
+			env:           map[string]string{"DB_HOST": "env-host"},
+			wantHost:      "env-host",
+			wantOpenConns: 40,
+		},
+		{
+			name:          "empty environment variable counts as unset",
+			args:          []string{"--config", yamlFile},
+			env:           map[string]string{"DB_HOST": ""},
+			wantHost:      "file-host",
+			wantOpenConns: 40,
+		},
+		{
+			name:          "flag overrides environment and file",
+			args:          []string{"--config", yamlFile, "--db-host", "flag-host", "--db-max-open-conns", "5"},
+			env:           map[string]string{"DB_HOST": "env-host", "DB_MAX_OPEN_CONNS": "30"},
+			wantHost:      "flag-host",
+			wantOpenConns: 5,
+		},
+		{
+			name:          "flag set to its default still overrides",
+			args:          []string{"--db-max-open-conns=25"},
+			env:           map[string]string{"DB_MAX_OPEN_CONNS": "30"},
+			wantHost:      "",
+			wantOpenConns: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"CONFIG_FILE", "DB_HOST", "DB_MAX_OPEN_CONNS"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.DBHost != tt.wantHost {
+				t.Errorf("DBHost = %q, want %q", cfg.DBHost, tt.wantHost)
+			}
+			if cfg.DBMaxOpenConns != tt.wantOpenConns {
+				t.Errorf("DBMaxOpenConns = %d, want %d", cfg.DBMaxOpenConns, tt.wantOpenConns)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		check    func(t *testing.T, cfg *Config)
+		wantErrs []string
+	}{
+		{
+			name:    "yaml lists and maps",
+			file:    "config.yaml",
+			content: "jwt:\n  issuers: [auth-a, auth-b]\nrole_permissions:\n  admin: [category:read, category:write]\n  viewer: [category:read]\n",
+			check: func(t *testing.T, cfg *Config) {
+				if want := []string{"auth-a", "auth-b"}; !reflect.DeepEqual(cfg.JWTIssuers, want) {
+					t.Errorf("JWTIssuers = %v, want %v", cfg.JWTIssuers, want)
+				}
+				want := map[string][]string{"admin": {"category:read", "category:write"}, "viewer": {"category:read"}}
+				if !reflect.DeepEqual(cfg.RolePermissions, want) {
+					t.Errorf("RolePermissions = %v, want %v", cfg.RolePermissions, want)
+				}
+			},
+		},
+		{
+			name:    "toml",
+			file:    "config.toml",
+			content: "[http]\nport = 9090\n\n[tracing]\nsample_ratio = 0.5\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTTPPort != "9090" {
+					t.Errorf("HTTPPort = %q, want 9090", cfg.HTTPPort)
+				}
+				if cfg.TracingSampleRatio != 0.5 {
+					t.Errorf("TracingSampleRatio = %v, want 0.5", cfg.TracingSampleRatio)
+				}
+			},
+		},
+		{
+			name:     "unknown and invalid settings are all reported",
+			file:     "config.yaml",
+			content:  "db:\n  hots: typo\n  max_open_conns: many\nshutdown:\n  drain_seconds: -1\n",
+			wantErrs: []string{"unknown setting db_hots", "DB_MAX_OPEN_CONNS must be a non-negative integer", "SHUTDOWN_DRAIN_SECONDS must be a non-negative integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", "")
+			for _, key := range []string{"JWT_ISSUERS", "ROLE_PERMISSIONS", "HTTP_PORT", "TRACING_SAMPLE_RATIO", "DB_MAX_OPEN_CONNS", "SHUTDOWN_DRAIN_SECONDS"} {
+				t.Setenv(key, "")
+			}
+
+			cfg, err := Load([]string{"--config", writeConfigFile(t, tt.file, tt.content)})
+			if len(tt.wantErrs) > 0 {
+				var problems Errors
+				if !errors.As(err, &problems) {
+					t.Fatalf("Load() error = %v, want configuration problems", err)
+				}
+				if len(problems) != len(tt.wantErrs) {
+					t.Errorf("Load() reported %d problems, want %d: %v", len(problems), len(tt.wantErrs), err)
+				}
+				for _, want := range tt.wantErrs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("Load() error = %v, want it to mention %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadRejectsUnsupportedFiles(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "unknown extension", path: writeConfigFile(t, "config.json", "{}")},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "missing.yaml")},
+		{name: "unparsable file", path: writeConfigFile(t, "config.yaml", "db: [")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load([]string{"--config", tt.path}); err == nil {
+				t.Error("Load() succeeded, want an error")
+			}
+		})
+	}
+}
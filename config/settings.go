@@ -0,0 +1,87 @@
+package config
+
+import "strings"
+
+// setting is a configuration value. Its key is the environment variable; the
+// flag is the key in lower case with dashes, e.g. --db-host, and in config
+// files nested keys are joined with underscores, e.g. db.host.
+type setting struct {
+	key   string
+	value string
+	usage string
+}
+
+// settings lists every configuration value with its default.
+var settings = []setting{
+	{"HTTP_HOST", "", "address the HTTP server listens on, empty for all interfaces"},
+	{"HTTP_PORT", "8080", "port of the HTTP server"},
+	{"HTTP_READ_HEADER_TIMEOUT_SECONDS", "10", "time allowed to read request headers"},
+	{"HTTP_READ_TIMEOUT_SECONDS", "30", "time allowed to read a whole request"},
+	{"HTTP_WRITE_TIMEOUT_SECONDS", "0", "time allowed to write a response, 0 for no limit (exports stream for long)"},
+	{"HTTP_IDLE_TIMEOUT_SECONDS", "120", "time keep-alive connections are kept idle"},
+	{"SHUTDOWN_DRAIN_SECONDS", "5", "time /readyz fails before the servers stop"},
+	{"SHUTDOWN_TIMEOUT_SECONDS", "10", "time allowed for in-flight requests and workers to finish on shutdown"},
+
+	{"GRPC_HOST", "", "address the gRPC server listens on, empty for all interfaces"},
+	{"GRPC_PORT", "50051", "port of the gRPC server"},
+	{"GRPC_MAX_RECV_MESSAGE_BYTES", "4194304", "largest message the gRPC server accepts"},
+	{"GRPC_MAX_SEND_MESSAGE_BYTES", "4194304", "largest message the gRPC server sends"},
+	{"GRPC_KEEPALIVE_SECONDS", "7200", "idle time after which the gRPC server pings clients"},
+	{"GRPC_KEEPALIVE_TIMEOUT_SECONDS", "20", "time the gRPC server waits for a ping reply"},
+
+	{"BOOK_GRPC_HOST", "", "host of the Book service"},
+	{"BOOK_GRPC_PORT", "50051", "gRPC port of the Book service"},
+	{"BOOK_GRPC_CALL_TIMEOUT_SECONDS", "5", "timeout of a call to the Book service"},
+	{"BOOK_GRPC_KEEPALIVE_SECONDS", "0", "idle time after which the Book connection is pinged, 0 to disable"},
+
+	{"DB_HOST", "", "PostgreSQL host"},
+	{"DB_PORT", "5432", "PostgreSQL port"},
+	{"DB_USER", "", "PostgreSQL user"},
+	{"DB_PASSWORD", "", "PostgreSQL password"},
+	{"DB_NAME", "", "PostgreSQL database"},
+	{"DB_SSLMODE", "prefer", "PostgreSQL sslmode"},
+	{"DB_MAX_OPEN_CONNS", "25", "maximum open database connections, 0 for no limit"},
+	{"DB_MAX_IDLE_CONNS", "10", "maximum idle database connections"},
+	{"DB_CONN_MAX_LIFETIME_MINUTES", "5", "time after which database connections are replaced, 0 for never"},
+
+	{"BASIC_AUTH_USER", "", "user of the admin routes; empty disables them"},
+	{"BASIC_AUTH_PASS", "", "password of the admin routes"},
+	{"METRICS_BASIC_AUTH", "false", "require the admin credentials for /metrics"},
+
+	{"LOG_FORMAT", "text", "log format, text or json"},
+	{"LOG_LEVEL", "debug", "minimum log level, e.g. info or debug"},
+
+	{"TRACING_EXPORTER", "none", "trace exporter, none, stdout or otlp"},
+	{"TRACING_OTLP_ENDPOINT", "localhost:4317", "host:port of the OTLP/gRPC collector"},
+	{"TRACING_OTLP_INSECURE", "true", "connect to the collector without TLS"},
+	{"TRACING_SAMPLE_RATIO", "1", "fraction of new traces recorded"},
+
+	{"JWKS_SOURCE", "", "file path or URL of a JWKS; empty to use JWT_PUBLIC_KEY_PATH"},
+	{"JWKS_REFRESH_MINUTES", "10", "how often the JWKS is reloaded"},
+	{"JWT_PUBLIC_KEY_PATH", "config/key/public_key.pem", "PEM public key tokens are verified with"},
+	{"JWT_PRIVATE_KEY_PATH", "", "PEM private key, only needed by the token subcommand"},
+	{"JWT_SIGNING_KEY_ID", "", "kid header of tokens signed by the token subcommand"},
+	{"JWT_ISSUERS", "go-jwt-auth-service", "comma-separated accepted issuers, empty for any"},
+	{"JWT_AUDIENCES", "", "comma-separated accepted audiences, empty for any"},
+	{"JWT_ALGORITHMS", "RS256", "comma-separated accepted signing algorithms"},
+	{"JWT_LEEWAY_SECONDS", "30", "clock skew tolerated when checking token times"},
+	{"REVOCATION_REFRESH_SECONDS", "30", "how often the token revocation list is reloaded"},
+
+	{"CATEGORY_RETENTION_DAYS", "30", "days deleted categories are kept before purging, 0 to keep them"},
+	{"OUTBOX_RETENTION_DAYS", "7", "days delivered outbox events are kept, 0 to keep them"},
+	{"IDEMPOTENCY_KEY_TTL_HOURS", "24", "hours responses to Idempotency-Key requests are kept"},
+	{"IDEMPOTENCY_KEY_LEASE_SECONDS", "60", "seconds an Idempotency-Key request may run before a retry can take the key over"},
+
+	{"ROLE_PERMISSIONS", defaultRolePermissions, `permissions per role as "role=perm,perm;role=perm"; the role "*" applies to every user`},
+}
+
+// defaultRolePermissions lets every user read, editors write and admins
+// additionally manage deleted categories.
+const defaultRolePermissions = "*=categories:read;" +
+	"editor=categories:read,categories:write;" +
+	"admin=categories:read,categories:write,categories:admin"
+
+// flagName returns the command-line flag of key.
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	sslModes          = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logFormats        = []string{"text", "json"}
+	tracingExporters  = []string{"none", "stdout", "otlp"}
+	signingAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+)
+
+// validate returns every missing or invalid value the server cannot start
+// with.
+func (c *Config) validate() Errors {
+	var problems Errors
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	required := []struct{ key, value string }{
+		{"DB_HOST", c.DBHost},
+		{"DB_USER", c.DBUser},
+		{"DB_NAME", c.DBName},
+		{"BOOK_GRPC_HOST", c.BookGRPCHost},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			problem("%s is required", r.key)
+		}
+	}
+
+	ports := []struct{ key, value string }{
+		{"HTTP_PORT", c.HTTPPort},
+		{"GRPC_PORT", c.GRPCPort},
+		{"DB_PORT", c.DBPort},
+		{"BOOK_GRPC_PORT", c.BookGRPCPort},
+	}
+	for _, p := range ports {
+		if port, err := strconv.Atoi(p.value); err != nil || port < 1 || port > 65535 {
+			problem("%s must be a port between 1 and 65535, got %q", p.key, p.value)
+		}
+	}
+
+	if !slices.Contains(sslModes, c.SSLMode) {
+		problem("DB_SSLMODE must be one of %v, got %q", sslModes, c.SSLMode)
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		problem("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns)
+	}
+
+	if c.ShutdownTimeoutSeconds == 0 {
+		problem("SHUTDOWN_TIMEOUT_SECONDS must be positive")
+	}
+	if c.IdempotencyKeyLeaseSeconds == 0 {
+		problem("IDEMPOTENCY_KEY_LEASE_SECONDS must be positive")
+	}
+	if c.BookGRPCCallTimeoutSeconds == 0 {
+		problem("BOOK_GRPC_CALL_TIMEOUT_SECONDS must be positive")
+	}
+	if c.GRPCMaxRecvMessageBytes == 0 || c.GRPCMaxSendMessageBytes == 0 {
+		problem("GRPC_MAX_RECV_MESSAGE_BYTES and GRPC_MAX_SEND_MESSAGE_BYTES must be positive")
+	}
+
+	if c.MetricsBasicAuth && c.BasicAuthUsername == "" {
+		problem("BASIC_AUTH_USER is required when METRICS_BASIC_AUTH is enabled")
+	}
+
+	if !slices.Contains(logFormats, c.LogFormat) {
+		problem("LOG_FORMAT must be one of %v, got %q", logFormats, c.LogFormat)
+	}
+	if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+		problem("LOG_LEVEL must be a log level such as info or debug, got %q", c.LogLevel)
+	}
+
+	if !slices.Contains(tracingExporters, c.TracingExporter) {
+		problem("TRACING_EXPORTER must be one of %v, got %q", tracingExporters, c.TracingExporter)
+	}
+	if c.TracingExporter == "otlp" && c.TracingOTLPEndpoint == "" {
+		problem("TRACING_OTLP_ENDPOINT is required when TRACING_EXPORTER is otlp")
+	}
+
+	if c.JWKSSource == "" {
+		if c.JWTPublicKeyPath == "" {
+			problem("JWT_PUBLIC_KEY_PATH is required when JWKS_SOURCE is not set")
+		} else if err := checkFile(c.JWTPublicKeyPath); err != nil {
+			problem("JWT_PUBLIC_KEY_PATH: %v", err)
+		}
+	}
+	if c.JWTPrivateKeyPath != "" {
+		if err := checkFile(c.JWTPrivateKeyPath); err != nil {
+			problem("JWT_PRIVATE_KEY_PATH: %v", err)
+		}
+	}
+	if len(c.JWTAlgorithms) == 0 {
+		problem("JWT_ALGORITHMS must list at least one algorithm")
+	}
+	for _, algorithm := range c.JWTAlgorithms {
+		if !slices.Contains(signingAlgorithms, algorithm) {
+			problem("JWT_ALGORITHMS must only contain %v, got %q", signingAlgorithms, algorithm)
+		}
+	}
+
+	if len(c.RolePermissions) == 0 {
+		problem("ROLE_PERMISSIONS grants no permissions")
+	}
+
+	return problems
+}
+
+// checkFile fails unless path is a readable regular file.
+func checkFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
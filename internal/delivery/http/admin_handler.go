@@ -0,0 +1,119 @@
+package http
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/grpcservice"
+	"category-service/internal/job"
+	"category-service/internal/usecase"
+	"category-service/pkg/shared/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	usecase    usecase.CategoryUsecase
+	reconciler *grpcservice.CategoryReconciler
+	purger     *job.CategoryPurger
+}
+
+func NewAdminHandler(uc usecase.CategoryUsecase, reconciler *grpcservice.CategoryReconciler, purger *job.CategoryPurger) *AdminHandler {
+	return &AdminHandler{usecase: uc, reconciler: reconciler, purger: purger}
+}
+
+// GetAllCategories is the admin variant of the category listing, which may
+// include soft-deleted categories.
+func (h *AdminHandler) GetAllCategories(c *gin.Context) {
+	var req domain.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	listCategories(c, h.usecase, &req)
+}
+
+type reconcileQuery struct {
+	DryRun    bool `form:"dryRun"`
+	Force     bool `form:"force"`
+	BatchSize int  `form:"batchSize" binding:"omitempty,min=1,max=1000"`
+}
+
+// ReconcileBookService starts a reconciliation in the background. Its
+// progress and report are read with GetReconcileRun.
+func (h *AdminHandler) ReconcileBookService(c *gin.Context) {
+	var query reconcileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	run, err := h.reconciler.Start(c.Request.Context(), grpcservice.ReconcileOptions{
+		DryRun:    query.DryRun,
+		Force:     query.Force,
+		BatchSize: query.BatchSize,
+	})
+	if err != nil {
+		response.FromError(c, err, "Failed to start reconciliation")
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Reconciliation started", run)
+}
+
+// GetReconcileRun returns the state of the most recent reconciliation
+// started on this instance, with its report once it finished.
+func (h *AdminHandler) GetReconcileRun(c *gin.Context) {
+	run, err := h.reconciler.LastRun()
+	if err != nil {
+		response.FromError(c, err, "Failed to get reconciliation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Reconciliation retrieved successfully", run)
+}
+
+type purgeQuery struct {
+	OlderThanDays *int `form:"olderThanDays" binding:"omitempty,min=0"`
+}
+
+// PurgeDeletedCategories permanently deletes trashed categories older than
+// olderThanDays, or than the configured retention period if it is omitted.
+func (h *AdminHandler) PurgeDeletedCategories(c *gin.Context) {
+	var query purgeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	var purged int
+	var err error
+	if query.OlderThanDays != nil {
+		purged, err = h.purger.PurgeOlderThan(c.Request.Context(), time.Duration(*query.OlderThanDays)*24*time.Hour)
+	} else {
+		purged, err = h.purger.PurgeExpired(c.Request.Context())
+	}
+	if err != nil {
+		response.FromError(c, err, "Failed to purge deleted categories")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Deleted categories purged successfully", gin.H{"purged": purged})
+}
+
+func (h *AdminHandler) PurgeCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.purger.PurgeCategory(c.Request.Context(), uint(id)); err != nil {
+		response.FromError(c, err, "Failed to purge category")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category permanently deleted", nil)
+}
@@ -3,8 +3,10 @@ package http
 import (
 	"category-service/internal/domain"
 	"category-service/internal/usecase"
+	"category-service/pkg/middleware"
 	"category-service/pkg/shared/response"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -27,10 +29,11 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 	book, err := h.usecase.CreateCategory(c.Request.Context(), &req)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to create category")
+		response.FromError(c, err, "Failed to create category")
 		return
 	}
 
+	setETag(c, book.Version)
 	response.Success(c, http.StatusCreated, "Category created successfully", book)
 }
 
@@ -41,9 +44,25 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 		return
 	}
 
-	categories, err := h.usecase.GetAllCategories(c.Request.Context(), &req)
+	if req.IncludeDeleted && !middleware.HasPermission(c, domain.PermissionCategoriesAdmin) {
+		middleware.DenyPermission(c, domain.PermissionCategoriesAdmin)
+		return
+	}
+
+	listCategories(c, h.usecase, &req)
+}
+
+// listCategories validates req and writes the paginated category list. It is
+// shared by the public and the admin listing.
+func listCategories(c *gin.Context, uc usecase.CategoryUsecase, req *domain.PaginationRequest) {
+	if err := req.Validate(); err != nil {
+		response.FromError(c, err, "Invalid query parameters")
+		return
+	}
+
+	categories, err := uc.GetAllCategories(c.Request.Context(), req)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to retrieve categories")
+		response.FromError(c, err, "Failed to retrieve categories")
 		return
 	}
 
@@ -52,6 +71,11 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 		PageSize:    req.Limit,
 		TotalPages:  categories.TotalPages,
 		TotalItems:  int(categories.Total),
+		NextCursor:  categories.NextCursor,
+		PrevCursor:  categories.PrevCursor,
+	}
+	if req.IsCursorMode() {
+		pagination.CurrentPage = 0
 	}
 
 	response.SuccessWithPagination(c, http.StatusOK, "Categories retrieved successfully", categories.Data, pagination)
@@ -66,7 +90,25 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 
 	category, err := h.usecase.GetCategoryByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Internal server error")
+		response.FromError(c, err, "Internal server error")
+		return
+	}
+
+	setETag(c, category.Version)
+	response.Success(c, http.StatusOK, "Category retrieved successfully", category)
+}
+
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	category, redirected, err := h.usecase.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		response.FromError(c, err, "Internal server error")
+		return
+	}
+
+	setETag(c, category.Version)
+	if redirected {
+		c.Header("Location", "/categories/slug/"+url.PathEscape(category.Slug))
+		response.Success(c, http.StatusMovedPermanently, "Category has moved to a new slug", category)
 		return
 	}
 
@@ -87,12 +129,19 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	req.ExpectedVersion = expectedVersion
+
 	book, err := h.usecase.UpdateCategory(c.Request.Context(), &req)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to update category")
+		response.FromError(c, err, "Failed to update category")
 		return
 	}
 
+	setETag(c, book.Version)
 	response.Success(c, http.StatusOK, "Category updated successfully", book)
 }
 
@@ -103,10 +152,164 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := h.usecase.DeleteCategory(c.Request.Context(), uint(id)); err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to delete category")
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	err = h.usecase.DeleteCategory(c.Request.Context(), &domain.DeleteCategoryRequest{ID: uint(id), ExpectedVersion: expectedVersion})
+	if err != nil {
+		response.FromError(c, err, "Failed to delete category")
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Category deleted successfully", nil)
 }
+
+func (h *CategoryHandler) GetCategoryHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	var req domain.CategoryHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+	req.ID = uint(id)
+
+	history, err := h.usecase.GetCategoryHistory(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve category history")
+		return
+	}
+
+	pagination := response.Pagination{
+		CurrentPage: req.Page,
+		PageSize:    req.Limit,
+		TotalPages:  history.TotalPages,
+		TotalItems:  int(history.Total),
+	}
+
+	response.SuccessWithPagination(c, http.StatusOK, "Category history retrieved successfully", history.Data, pagination)
+}
+
+func (h *CategoryHandler) GetTrash(c *gin.Context) {
+	var req domain.TrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	categories, err := h.usecase.GetTrash(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve deleted categories")
+		return
+	}
+
+	pagination := response.Pagination{
+		CurrentPage: req.Page,
+		PageSize:    req.Limit,
+		TotalPages:  categories.TotalPages,
+		TotalItems:  int(categories.Total),
+	}
+
+	response.SuccessWithPagination(c, http.StatusOK, "Deleted categories retrieved successfully", categories.Data, pagination)
+}
+
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	var req domain.RestoreCategoryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+	req.ID = uint(id)
+
+	category, err := h.usecase.RestoreCategory(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err, "Failed to restore category")
+		return
+	}
+
+	setETag(c, category.Version)
+	response.Success(c, http.StatusOK, "Category restored successfully", category)
+}
+
+func (h *CategoryHandler) BatchCategories(c *gin.Context) {
+	var req domain.BatchCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	result, err := h.usecase.BatchCategories(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err, "Failed to process batch")
+		return
+	}
+
+	if !result.Committed {
+		response.ErrorWithData(c, http.StatusUnprocessableEntity, "BATCH_FAILED", "Batch was rolled back because an operation failed", result)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Batch processed successfully", result)
+}
+
+func (h *CategoryHandler) GetCategoryChildren(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	children, err := h.usecase.GetCategoryChildren(c.Request.Context(), uint(id))
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve child categories")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Child categories retrieved successfully", children)
+}
+
+func (h *CategoryHandler) GetCategoryAncestors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	ancestors, err := h.usecase.GetCategoryAncestors(c.Request.Context(), uint(id))
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve category ancestors")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category ancestors retrieved successfully", ancestors)
+}
+
+func (h *CategoryHandler) GetCategorySubtree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	subtree, err := h.usecase.GetCategorySubtree(c.Request.Context(), uint(id))
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve category subtree")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category subtree retrieved successfully", subtree)
+}
@@ -0,0 +1,294 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"category-service/internal/domain"
+	"category-service/pkg/shared/response"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxImportBodySize = 20 << 20
+	maxNDJSONLineSize = 1 << 20
+)
+
+var categoryCSVHeader = []string{"id", "name", "slug", "parent_id", "parent_name", "created_at", "updated_at"}
+
+func (h *CategoryHandler) ExportCategories(c *gin.Context) {
+	var req domain.ExportCategoriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = domain.TransferFormatCSV
+	}
+
+	filename := fmt.Sprintf("categories-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+
+	// The status line is only sent with the first row, so errors of the
+	// query itself are still reported as a regular error response.
+	var write func(row *domain.CategoryExportRow) error
+	var flush func() error
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		if format == domain.TransferFormatNDJSON {
+			c.Header("Content-Type", "application/x-ndjson")
+			encoder := json.NewEncoder(c.Writer)
+			write = func(row *domain.CategoryExportRow) error { return encoder.Encode(row) }
+			flush = func() error { return nil }
+			c.Status(http.StatusOK)
+			return nil
+		}
+
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		write = func(row *domain.CategoryExportRow) error { return writer.Write(csvRecord(row)) }
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+		c.Status(http.StatusOK)
+		return writer.Write(categoryCSVHeader)
+	}
+
+	rows := 0
+	err := h.usecase.ExportCategories(c.Request.Context(), func(row *domain.CategoryExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+		rows++
+		if rows%500 == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil && !started {
+		response.FromError(c, err, "Failed to export categories")
+		return
+	}
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		_ = c.Error(err)
+		abortResponse(c)
+		return
+	}
+	c.Writer.Flush()
+}
+
+// abortResponse closes the connection of a response whose status line was
+// already sent. The chunked body then lacks its final chunk, so clients see a
+// truncated download as an error instead of a complete file.
+func abortResponse(c *gin.Context) {
+	c.Abort()
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		return
+	}
+	_ = conn.Close()
+}
+
+func (h *CategoryHandler) ImportCategories(c *gin.Context) {
+	var req domain.ImportCategoriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = domain.TransferFormatCSV
+		if strings.Contains(c.ContentType(), "ndjson") {
+			req.Format = domain.TransferFormatNDJSON
+		}
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodySize)
+
+	var source domain.CategoryImportSource
+	if req.Format == domain.TransferFormatNDJSON {
+		source = newNDJSONImportSource(body)
+	} else {
+		csvSource, err := newCSVImportSource(body)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		source = csvSource
+	}
+
+	report, err := h.usecase.ImportCategories(c.Request.Context(), source, &req)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.Error(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Import file exceeds %d bytes", maxImportBodySize))
+		return
+	}
+	if err != nil {
+		response.FromError(c, err, "Failed to import categories")
+		return
+	}
+
+	message := "Categories imported successfully"
+	if req.DryRun {
+		message = "Dry run completed, no changes were saved"
+	}
+	response.Success(c, http.StatusOK, message, report)
+}
+
+func csvRecord(row *domain.CategoryExportRow) []string {
+	record := []string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		row.Name,
+		row.Slug,
+		"",
+		"",
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if row.ParentID != nil {
+		record[3] = strconv.FormatUint(uint64(*row.ParentID), 10)
+	}
+	if row.ParentName != nil {
+		record[4] = *row.ParentName
+	}
+	return record
+}
+
+// csvImportSource reads import rows from CSV with a header line. Only the id,
+// name, parent_id and parent_name columns are used, so exported files can be
+// imported as they are.
+type csvImportSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportSource(r io.Reader) (*csvImportSource, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV header line is missing")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("CSV header must contain a name column")
+	}
+
+	return &csvImportSource{reader: reader, columns: columns}, nil
+}
+
+func (s *csvImportSource) Next() (*domain.CategoryImportRow, int, error) {
+	record, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, 0, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, parseErr.StartLine, domain.NewValidationError("INVALID_CSV", parseErr.Err.Error())
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := s.reader.FieldPos(0)
+
+	row := &domain.CategoryImportRow{Name: strings.TrimSpace(s.field(record, "name"))}
+
+	if value := s.field(record, "id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, line, domain.NewValidationError("INVALID_ID", "id must be a positive integer")
+		}
+		row.ID = uint(id)
+	}
+
+	if value := s.field(record, "parent_id"); value != "" {
+		parentID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, line, domain.NewValidationError("INVALID_PARENT_ID", "parent_id must be a positive integer")
+		}
+		id := uint(parentID)
+		row.ParentID = &id
+	}
+
+	if value := strings.TrimSpace(s.field(record, "parent_name")); value != "" {
+		row.ParentName = &value
+	}
+
+	return row, line, nil
+}
+
+func (s *csvImportSource) field(record []string, column string) string {
+	i, ok := s.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// ndjsonImportSource reads one JSON object per line; blank lines are skipped.
+type ndjsonImportSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONImportSource(r io.Reader) *ndjsonImportSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &ndjsonImportSource{scanner: scanner}
+}
+
+func (s *ndjsonImportSource) Next() (*domain.CategoryImportRow, int, error) {
+	for s.scanner.Scan() {
+		s.line++
+		data := bytes.TrimSpace(s.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var row domain.CategoryImportRow
+		if err := json.Unmarshal(data, &row); err != nil {
+			return nil, s.line, domain.NewValidationError("INVALID_JSON", err.Error())
+		}
+		row.Name = strings.TrimSpace(row.Name)
+		return &row, s.line, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, s.line + 1, err
+	}
+	return nil, s.line, io.EOF
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"category-service/internal/domain"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// importResult is one value returned by CategoryImportSource.Next.
+type importResult struct {
+	row  *domain.CategoryImportRow
+	line int
+	code string
+}
+
+func readImportSource(t *testing.T, source domain.CategoryImportSource) []importResult {
+	t.Helper()
+
+	var results []importResult
+	for {
+		row, line, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return results
+		}
+
+		result := importResult{row: row, line: line}
+		if err != nil {
+			domainErr, ok := domain.AsError(err)
+			if !ok {
+				t.Fatalf("Next() error = %v, want a domain error", err)
+			}
+			result.code = domainErr.Code
+		}
+		results = append(results, result)
+	}
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+func TestCSVImportSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []importResult
+		wantErr string
+	}{
+		{
+			name:  "all columns",
+			input: "id,name,parent_id,parent_name\n1,Fiction,,\n2, Fantasy ,1,\n3,Poetry,,Fiction\n",
+			want: []importResult{
+				{row: &domain.CategoryImportRow{ID: 1, Name: "Fiction"}, line: 2},
+				{row: &domain.CategoryImportRow{ID: 2, Name: "Fantasy", ParentID: uintPtr(1)}, line: 3},
+				{row: &domain.CategoryImportRow{ID: 3, Name: "Poetry", ParentName: stringPtr("Fiction")}, line: 4},
+			},
+		},
+		{
+			name:  "export columns in any order and case",
+			input: "\ufeffName,Slug,ID,Created_At\nFiction,fiction,5,2024-01-01T00:00:00Z\n",
+			want: []importResult{
+				{row: &domain.CategoryImportRow{ID: 5, Name: "Fiction"}, line: 2},
+			},
+		},
+		{
+			name:  "short records",
+			input: "name,id,parent_id\nFiction\n",
+			want: []importResult{
+				{row: &domain.CategoryImportRow{Name: "Fiction"}, line: 2},
+			},
+		},
+		{
+			name:  "invalid rows are reported and skipped",
+			input: "id,name,parent_id\nabc,Fiction,\n2,Fantasy,-1\n3,\"Poetry\n",
+			want: []importResult{
+				{line: 2, code: "INVALID_ID"},
+				{line: 3, code: "INVALID_PARENT_ID"},
+				{line: 4, code: "INVALID_CSV"},
+			},
+		},
+		{
+			name:    "missing header",
+			input:   "",
+			wantErr: "CSV header line is missing",
+		},
+		{
+			name:    "missing name column",
+			input:   "id,parent_id\n1,\n",
+			wantErr: "CSV header must contain a name column",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := newCSVImportSource(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("newCSVImportSource() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newCSVImportSource() error = %v", err)
+			}
+
+			if got := readImportSource(t, source); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %s, want %s", formatImportResults(got), formatImportResults(tt.want))
+			}
+		})
+	}
+}
+
+func TestNDJSONImportSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []importResult
+	}{
+		{
+			name:  "rows",
+			input: `{"id":1,"name":"Fiction"}` + "\n" + `{"name":" Fantasy ","parentId":1}` + "\n" + `{"name":"Poetry","parentName":"Fiction"}`,
+			want: []importResult{
+				{row: &domain.CategoryImportRow{ID: 1, Name: "Fiction"}, line: 1},
+				{row: &domain.CategoryImportRow{Name: "Fantasy", ParentID: uintPtr(1)}, line: 2},
+				{row: &domain.CategoryImportRow{Name: "Poetry", ParentName: stringPtr("Fiction")}, line: 3},
+			},
+		},
+		{
+			name:  "blank lines are skipped but counted",
+			input: "\n" + `{"name":"Fiction"}` + "\n  \n\n" + `{"name":"Poetry"}` + "\n",
+			want: []importResult{
+				{row: &domain.CategoryImportRow{Name: "Fiction"}, line: 2},
+				{row: &domain.CategoryImportRow{Name: "Poetry"}, line: 5},
+			},
+		},
+		{
+			name:  "invalid rows are reported and skipped",
+			input: `{"name":` + "\n" + `{"id":-1,"name":"Fiction"}` + "\n" + `{"name":"Poetry"}`,
+			want: []importResult{
+				{line: 1, code: "INVALID_JSON"},
+				{line: 2, code: "INVALID_JSON"},
+				{row: &domain.CategoryImportRow{Name: "Poetry"}, line: 3},
+			},
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readImportSource(t, newNDJSONImportSource(strings.NewReader(tt.input)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %s, want %s", formatImportResults(got), formatImportResults(tt.want))
+			}
+		})
+	}
+}
+
+func TestNDJSONImportSourceLineTooLong(t *testing.T) {
+	input := `{"name":"Fiction"}` + "\n" + `{"name":"` + strings.Repeat("a", maxNDJSONLineSize) + `"}` + "\n"
+	source := newNDJSONImportSource(strings.NewReader(input))
+
+	if _, _, err := source.Next(); err != nil {
+		t.Fatalf("first Next() error = %v", err)
+	}
+	_, line, err := source.Next()
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("second Next() error = %v, want a read error", err)
+	}
+	if _, ok := domain.AsError(err); ok {
+		t.Errorf("second Next() error = %v, want an error that aborts the import", err)
+	}
+	if line != 2 {
+		t.Errorf("second Next() line = %d, want 2", line)
+	}
+}
+
+func formatImportResults(results []importResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.row == nil {
+			parts = append(parts, fmt.Sprintf("{line %d %s}", result.line, result.code))
+			continue
+		}
+		part := fmt.Sprintf("{line %d id %d %q", result.line, result.row.ID, result.row.Name)
+		if result.row.ParentID != nil {
+			part += fmt.Sprintf(" parent %d", *result.row.ParentID)
+		}
+		if result.row.ParentName != nil {
+			part += fmt.Sprintf(" parent %q", *result.row.ParentName)
+		}
+		parts = append(parts, part+"}")
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
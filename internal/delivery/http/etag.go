@@ -0,0 +1,45 @@
+package http
+
+import (
+	"category-service/pkg/shared/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag exposes the version of a category as its entity tag.
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+// ifMatchVersion returns the category version required by the If-Match
+// header, or nil if the header is absent or "*". It writes a 400 response and
+// returns false if the header is not a single category ETag, and a 412
+// response for a weak ETag, which never matches under the strong comparison
+// If-Match requires (RFC 7232, section 3.1).
+func ifMatchVersion(c *gin.Context) (*uint, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, true
+	}
+
+	if strings.HasPrefix(value, "W/") {
+		response.ErrorWithCode(c, http.StatusPreconditionFailed, "PRECONDITION_FAILED", "If-Match requires a strong ETag")
+		return nil, false
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		response.Error(c, http.StatusBadRequest, "Invalid If-Match header")
+		return nil, false
+	}
+
+	version, err := strconv.ParseUint(value[1:len(value)-1], 10, 0)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid If-Match header")
+		return nil, false
+	}
+
+	expected := uint(version)
+	return &expected, true
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	version := func(v uint) *uint { return &v }
+
+	tests := []struct {
+		name       string
+		header     string
+		want       *uint
+		wantOK     bool
+		wantStatus int
+	}{
+		{name: "absent", header: "", wantOK: true},
+		{name: "wildcard", header: "*", wantOK: true},
+		{name: "wildcard with spaces", header: " * ", wantOK: true},
+		{name: "strong etag", header: `"3"`, want: version(3), wantOK: true},
+		{name: "strong etag with spaces", header: `  "12" `, want: version(12), wantOK: true},
+		{name: "version zero", header: `"0"`, want: version(0), wantOK: true},
+		{name: "weak etag", header: `W/"3"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "unquoted", header: "3", wantStatus: http.StatusBadRequest},
+		{name: "single quote", header: `"`, wantStatus: http.StatusBadRequest},
+		{name: "empty etag", header: `""`, wantStatus: http.StatusBadRequest},
+		{name: "not a version", header: `"abc"`, wantStatus: http.StatusBadRequest},
+		{name: "negative", header: `"-1"`, wantStatus: http.StatusBadRequest},
+		{name: "list of etags", header: `"1", "2"`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodPut, "/categories/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			got, ok := ifMatchVersion(c)
+			if ok != tt.wantOK {
+				t.Fatalf("ifMatchVersion() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if recorder.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+				}
+				return
+			}
+
+			if c.Writer.Written() {
+				t.Errorf("ifMatchVersion() wrote a response with status %d", recorder.Code)
+			}
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || *got != *tt.want:
+				t.Errorf("ifMatchVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
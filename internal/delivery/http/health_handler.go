@@ -0,0 +1,115 @@
+package http
+
+import (
+	"category-service/pkg/shared/response"
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout bounds each dependency check of a readiness probe.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker checks that a dependency is usable.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// DependencyStatus is the outcome of checking one dependency.
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+	// Critical dependencies fail the readiness probe when they are down.
+	Critical bool `json:"critical"`
+}
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	critical     map[string]HealthChecker
+	optional     map[string]HealthChecker
+	shuttingDown atomic.Bool
+}
+
+// NewHealthHandler reports ready while every critical dependency passes its
+// check. Optional dependencies, such as services whose outage the instance
+// tolerates, are only reported. Both are keyed by the name shown in the
+// readiness body.
+func NewHealthHandler(critical, optional map[string]HealthChecker) *HealthHandler {
+	return &HealthHandler{critical: critical, optional: optional}
+}
+
+// SetShuttingDown makes the readiness probe fail from now on, so that load
+// balancers stop sending requests before the servers stop.
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness reports that the process is serving requests. It does not check
+// dependencies, so an outage of one does not get the instance restarted.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Alive", nil)
+}
+
+// Readiness reports whether the instance can serve requests, with the status
+// of every dependency.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		response.ErrorWithData(c, http.StatusServiceUnavailable, "SHUTTING_DOWN", "Shutting down", gin.H{"shuttingDown": true})
+		return
+	}
+
+	dependencies := h.checkDependencies(c.Request.Context())
+	for _, dependency := range dependencies {
+		if dependency.Critical && dependency.Status != "up" {
+			response.ErrorWithData(c, http.StatusServiceUnavailable, "NOT_READY", "Not ready", gin.H{"dependencies": dependencies})
+			return
+		}
+	}
+
+	response.Success(c, http.StatusOK, "Ready", gin.H{"dependencies": dependencies})
+}
+
+// checkDependencies checks all dependencies concurrently.
+func (h *HealthHandler) checkDependencies(ctx context.Context) map[string]*DependencyStatus {
+	statuses := make(map[string]*DependencyStatus, len(h.critical)+len(h.optional))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	checkAll := func(dependencies map[string]HealthChecker, critical bool) {
+		for name, checker := range dependencies {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				status := check(ctx, checker)
+				status.Critical = critical
+				mu.Lock()
+				statuses[name] = status
+				mu.Unlock()
+			}()
+		}
+	}
+	checkAll(h.critical, true)
+	checkAll(h.optional, false)
+	wg.Wait()
+
+	return statuses
+}
+
+func check(ctx context.Context, checker HealthChecker) *DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Ping(ctx)
+	status := &DependencyStatus{Status: "up", LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		status.Status = "down"
+		status.Error = err.Error()
+	}
+	return status
+}
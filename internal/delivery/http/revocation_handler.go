@@ -0,0 +1,112 @@
+package http
+
+import (
+	"category-service/internal/domain"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/shared/response"
+	"category-service/pkg/token"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevocationHandler manages the token revocation list consulted when
+// authenticating requests.
+type RevocationHandler struct {
+	revocations *token.RevocationList
+}
+
+func NewRevocationHandler(revocations *token.RevocationList) *RevocationHandler {
+	return &RevocationHandler{revocations: revocations}
+}
+
+func (h *RevocationHandler) GetRevocations(c *gin.Context) {
+	tokens, err := h.revocations.RevokedTokens(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve token revocations")
+		return
+	}
+
+	users, err := h.revocations.UserTokenRevocations(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err, "Failed to retrieve token revocations")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token revocations retrieved successfully", gin.H{
+		"tokens": tokens,
+		"users":  users,
+	})
+}
+
+func (h *RevocationHandler) RevokeToken(c *gin.Context) {
+	var req domain.RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	revokedToken := &sharedDomain.RevokedToken{JTI: req.JTI, Reason: req.Reason, ExpiresAt: req.ExpiresAt}
+	if err := h.revocations.RevokeToken(c.Request.Context(), revokedToken); err != nil {
+		response.FromError(c, err, "Failed to revoke token")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Token revoked successfully", revokedToken)
+}
+
+func (h *RevocationHandler) RevokeUserTokens(c *gin.Context) {
+	var req domain.RevokeUserTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	revocation := &sharedDomain.UserTokenRevocation{UserID: req.UserID, RevokedBefore: time.Now(), Reason: req.Reason}
+	if req.RevokedBefore != nil {
+		revocation.RevokedBefore = *req.RevokedBefore
+	}
+
+	if err := h.revocations.RevokeUserTokens(c.Request.Context(), revocation); err != nil {
+		response.FromError(c, err, "Failed to revoke user tokens")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "User tokens revoked successfully", revocation)
+}
+
+func (h *RevocationHandler) UnrevokeToken(c *gin.Context) {
+	deleted, err := h.revocations.UnrevokeToken(c.Request.Context(), c.Param("jti"))
+	if err != nil {
+		response.FromError(c, err, "Failed to delete token revocation")
+		return
+	}
+	if !deleted {
+		response.FromError(c, domain.ErrRevocationNotFound, "")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token revocation deleted successfully", nil)
+}
+
+func (h *RevocationHandler) UnrevokeUserTokens(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	deleted, err := h.revocations.UnrevokeUserTokens(c.Request.Context(), uint(userID))
+	if err != nil {
+		response.FromError(c, err, "Failed to delete user token revocation")
+		return
+	}
+	if !deleted {
+		response.FromError(c, domain.ErrRevocationNotFound, "")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User token revocation deleted successfully", nil)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+type actorContextKey struct{}
+
+// WithActor returns a copy of ctx carrying the ID of the authenticated user,
+// which is recorded as the actor of category changes.
+func WithActor(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, userID)
+}
+
+// ActorFromContext returns the user set by WithActor, or nil for changes made
+// by the service itself.
+func ActorFromContext(ctx context.Context) *uint {
+	userID, ok := ctx.Value(actorContextKey{}).(uint)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
@@ -1 +1,6 @@
 package domain
+
+// MaxCategoryDepth is the number of levels the category tree may have. Walks
+// of the tree stop after as many levels, as a safeguard against corrupted
+// parent references.
+const MaxCategoryDepth = 64
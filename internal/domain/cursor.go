@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// CategoryCursor marks a position in the category list ordered by
+// (created_at, id). Backward cursors page towards the start of the list.
+type CategoryCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque representation handed out to clients.
+func (c CategoryCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCategoryCursor(value string) (*CategoryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor CategoryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 || cursor.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
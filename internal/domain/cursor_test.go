@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCategoryCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor CategoryCursor
+	}{
+		{name: "forward", cursor: CategoryCursor{CreatedAt: createdAt, ID: 42}},
+		{name: "backward", cursor: CategoryCursor{CreatedAt: createdAt, ID: 7, Backward: true}},
+		{name: "large id", cursor: CategoryCursor{CreatedAt: createdAt, ID: 1<<32 - 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCategoryCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCategoryCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.ID != tt.cursor.ID || got.Backward != tt.cursor.Backward {
+				t.Errorf("DecodeCategoryCursor() = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCategoryCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "empty", value: ""},
+		{name: "not base64", value: "not a cursor!"},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte(`{"c":"2024-03-01T12:30:00Z","i":1}`))},
+		{name: "not json", value: encode("cursor")},
+		{name: "missing id", value: encode(`{"c":"2024-03-01T12:30:00Z"}`)},
+		{name: "zero id", value: encode(`{"c":"2024-03-01T12:30:00Z","i":0}`)},
+		{name: "missing time", value: encode(`{"i":1}`)},
+		{name: "bad time", value: encode(`{"c":"yesterday","i":1}`)},
+		{name: "negative id", value: encode(`{"c":"2024-03-01T12:30:00Z","i":-1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCategoryCursor(tt.value)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCategoryCursor(%q) = %+v, %v; want ErrInvalidCursor", tt.value, cursor, err)
+			}
+		})
+	}
+}
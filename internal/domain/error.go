@@ -0,0 +1,93 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies domain errors independently of the transport, so the
+// HTTP and gRPC layers can map them to their own status codes.
+type ErrorKind string
+
+const (
+	KindNotFound            ErrorKind = "not_found"
+	KindConflict            ErrorKind = "conflict"
+	KindValidation          ErrorKind = "validation"
+	KindUpstreamUnavailable ErrorKind = "upstream_unavailable"
+	KindPreconditionFailed  ErrorKind = "precondition_failed"
+)
+
+// Error is a domain error with a stable, machine-readable code.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap returns a copy of e with err as its cause.
+func (e *Error) Wrap(err error) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Err: err}
+}
+
+// Is matches errors of the same kind and code, so that errors.Is works for
+// sentinel errors even after they were copied with a different cause.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func NewValidationError(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func NewUpstreamUnavailableError(code, message string, err error) *Error {
+	return &Error{Kind: KindUpstreamUnavailable, Code: code, Message: message, Err: err}
+}
+
+func NewPreconditionFailedError(code, message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: message}
+}
+
+// AsError returns the domain error wrapped in err, if any.
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+var (
+	ErrCategoryNotFound        = NewNotFoundError("CATEGORY_NOT_FOUND", "category not found")
+	ErrCategoryNotInTrash      = NewNotFoundError("CATEGORY_NOT_IN_TRASH", "category is not in the trash")
+	ErrCategoryNameTaken       = NewConflictError("CATEGORY_NAME_TAKEN", "a category with this name already exists")
+	ErrCategoryIDTaken         = NewConflictError("CATEGORY_ID_TAKEN", "a category with this id already exists")
+	ErrCategorySlugTaken       = NewConflictError("CATEGORY_SLUG_TAKEN", "a category with this slug already exists")
+	ErrConflict                = NewConflictError("CONFLICT", "the request conflicts with existing data")
+	ErrParentNotFound          = NewValidationError("PARENT_NOT_FOUND", "parent category not found")
+	ErrCategoryCycle           = NewValidationError("CATEGORY_CYCLE", "category cannot be moved below itself or one of its descendants")
+	ErrCategoryTooDeep         = NewValidationError("CATEGORY_TOO_DEEP", "categories cannot be nested more than 64 levels deep")
+	ErrCategoryVersionMismatch = NewPreconditionFailedError("CATEGORY_VERSION_MISMATCH", "category was modified since the given version")
+	ErrCategoryHasChildren     = NewConflictError("CATEGORY_HAS_CHILDREN", "category still has child categories")
+	ErrRetentionDisabled       = NewValidationError("RETENTION_DISABLED", "category retention is disabled, olderThanDays is required")
+	ErrRevocationNotFound      = NewNotFoundError("REVOCATION_NOT_FOUND", "revocation not found")
+	ErrReconcileRunning        = NewConflictError("RECONCILE_RUNNING", "a reconciliation is already running")
+	ErrReconcileNotFound       = NewNotFoundError("RECONCILE_NOT_FOUND", "no reconciliation has been started")
+	ErrInvalidCursor           = NewValidationError("INVALID_CURSOR", "invalid cursor")
+	ErrBookServiceUnavailable  = NewUpstreamUnavailableError("BOOK_SERVICE_UNAVAILABLE", "book service is unavailable", nil)
+)
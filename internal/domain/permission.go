@@ -0,0 +1,39 @@
+package domain
+
+const (
+	PermissionCategoriesRead  = "categories:read"
+	PermissionCategoriesWrite = "categories:write"
+	PermissionCategoriesAdmin = "categories:admin"
+
+	// AnyRole is the RolePermissions entry granted to every authenticated
+	// user, including tokens without roles.
+	AnyRole = "*"
+)
+
+// RolePermissions maps token roles to the permissions they grant.
+type RolePermissions map[string][]string
+
+// PermissionSet is the set of permissions held by a caller.
+type PermissionSet map[string]bool
+
+func (s PermissionSet) Has(permission string) bool {
+	return s[permission]
+}
+
+// Grant resolves the permissions of a token: those of AnyRole, those of each
+// of its roles and its scopes.
+func (r RolePermissions) Grant(claims *TokenClaims) PermissionSet {
+	permissions := PermissionSet{}
+	for _, permission := range r[AnyRole] {
+		permissions[permission] = true
+	}
+	for _, role := range claims.Roles {
+		for _, permission := range r[role] {
+			permissions[permission] = true
+		}
+	}
+	for _, scope := range claims.Scopes() {
+		permissions[scope] = true
+	}
+	return permissions
+}
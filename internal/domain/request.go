@@ -1,17 +1,163 @@
 package domain
 
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
 type PaginationRequest struct {
-	Page  int `form:"page" binding:"required,min=1"`
+	// Page is required in offset mode and ignored in cursor mode.
+	Page  int `form:"page" binding:"omitempty,min=1"`
 	Limit int `form:"limit" binding:"required,min=1,max=100"`
+
+	// Pagination selects between the default page/limit mode ("offset") and
+	// keyset pagination ("cursor"). Passing a Cursor implies cursor mode.
+	Pagination string `form:"pagination" binding:"omitempty,oneof=offset cursor"`
+	Cursor     string `form:"cursor" binding:"max=512"`
+
+	// Search matches anywhere in the name, Prefix only at its start. Both
+	// are case-insensitive.
+	Search string `form:"search" binding:"max=100"`
+	Prefix string `form:"prefix" binding:"max=100"`
+
+	CreatedFrom *time.Time `form:"createdFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"createdTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedFrom *time.Time `form:"updatedFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo   *time.Time `form:"updatedTo" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	Sort  string `form:"sort" binding:"omitempty,oneof=id name createdAt updatedAt"`
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+
+	// IncludeDeleted also returns soft-deleted categories. Admin only.
+	IncludeDeleted bool `form:"includeDeleted"`
+}
+
+func (r *PaginationRequest) IsCursorMode() bool {
+	return r.Pagination == "cursor" || r.Cursor != ""
+}
+
+// Validate checks constraints between fields that binding tags cannot express.
+func (r *PaginationRequest) Validate() error {
+	if r.IsCursorMode() {
+		if r.Sort != "" && r.Sort != "createdAt" {
+			return NewValidationError("INVALID_SORT", "cursor pagination only supports sorting by createdAt")
+		}
+	} else if r.Page < 1 {
+		return NewValidationError("PAGE_REQUIRED", "page is required")
+	}
+	if r.CreatedFrom != nil && r.CreatedTo != nil && r.CreatedFrom.After(*r.CreatedTo) {
+		return NewValidationError("INVALID_DATE_RANGE", "createdFrom must not be after createdTo")
+	}
+	if r.UpdatedFrom != nil && r.UpdatedTo != nil && r.UpdatedFrom.After(*r.UpdatedTo) {
+		return NewValidationError("INVALID_DATE_RANGE", "updatedFrom must not be after updatedTo")
+	}
+	return nil
 }
 
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required"`
-	Bio  string `json:"bio"`
+	Name     string `json:"name" binding:"required"`
+	Bio      string `json:"bio"`
+	ParentID *uint  `json:"parentId"`
+
+	// ID is only set by imports that preserve ids across environments.
+	ID uint `json:"-"`
 }
 
 type UpdateCategoryRequest struct {
-	ID   uint    `json:"id" binding:"required"`
-	Name *string `json:"name" binding:"required"`
-	Bio  *string `json:"bio"`
+	ID       uint         `json:"id" binding:"required"`
+	Name     *string      `json:"name"`
+	Bio      *string      `json:"bio"`
+	ParentID OptionalUint `json:"parentId"`
+	// ExpectedVersion, if set, makes the update fail unless the category is
+	// still at this version.
+	ExpectedVersion *uint `json:"-"`
+}
+
+type DeleteCategoryRequest struct {
+	ID              uint
+	ExpectedVersion *uint
+}
+
+// OptionalUint distinguishes an absent JSON field from an explicit null, so
+// that PATCH requests can leave a value untouched or clear it.
+type OptionalUint struct {
+	Set   bool
+	Value *uint
+}
+
+func (o *OptionalUint) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		o.Value = nil
+		return nil
+	}
+
+	var value uint
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
+
+type TrashRequest struct {
+	Page  int `form:"page" binding:"required,min=1"`
+	Limit int `form:"limit" binding:"required,min=1,max=100"`
+}
+
+type CategoryHistoryRequest struct {
+	ID    uint `form:"-"`
+	Page  int  `form:"page" binding:"required,min=1"`
+	Limit int  `form:"limit" binding:"required,min=1,max=100"`
+}
+
+// RestoreCategoryRequest restores a soft-deleted category. If a live category
+// took its name in the meantime, the restore fails unless Name provides a new
+// one or AutoRename is set.
+type RestoreCategoryRequest struct {
+	ID         uint    `json:"-"`
+	Name       *string `json:"name" binding:"omitempty,min=1"`
+	AutoRename bool    `json:"autoRename"`
+}
+
+type RevokeTokenRequest struct {
+	JTI string `json:"jti" binding:"required,max=255"`
+	// ExpiresAt is the expiry of the token; the revocation is dropped after it.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Reason    string     `json:"reason" binding:"max=255"`
+}
+
+// RevokeUserTokensRequest revokes all tokens of a user issued before
+// RevokedBefore, which defaults to now.
+type RevokeUserTokensRequest struct {
+	UserID        uint       `json:"userId" binding:"required"`
+	RevokedBefore *time.Time `json:"revokedBefore"`
+	Reason        string     `json:"reason" binding:"max=255"`
+}
+
+const (
+	BatchModeAtomic  = "atomic"
+	BatchModePartial = "partial"
+
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+
+	MaxBatchOperations = 500
+)
+
+// BatchCategoryRequest applies several writes in one transaction. In atomic
+// mode (the default) any failing operation rolls back the whole batch; in
+// partial mode only the failing operations are skipped.
+type BatchCategoryRequest struct {
+	Mode       string                   `json:"mode" binding:"omitempty,oneof=atomic partial"`
+	Operations []BatchCategoryOperation `json:"operations" binding:"required,min=1,max=500,dive"`
+}
+
+type BatchCategoryOperation struct {
+	Op       string       `json:"op" binding:"required,oneof=create update delete"`
+	ID       uint         `json:"id"`
+	Name     *string      `json:"name"`
+	ParentID OptionalUint `json:"parentId"`
 }
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// maxRequestIDLength bounds request IDs accepted from clients, which end up
+// in every log entry of the request.
+const maxRequestIDLength = 128
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID that correlates the
+// logs of a request, including calls to other services.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the ID set by WithRequestID, or "" outside of
+// a request.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// EnsureRequestID returns the request ID a client sent, or a new random one
+// if it sent none or one that is too long or contains characters other than
+// letters, digits and "-_.:".
+func EnsureRequestID(requestID string) string {
+	if isValidRequestID(requestID) {
+		return requestID
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,96 @@
 package domain
 
+import (
+	sharedDomain "category-service/pkg/shared/domain"
+	"time"
+)
+
 type PaginatedResponse struct {
 	Data       interface{} `json:"data"`
 	Total      int64       `json:"total"`
 	Page       int         `json:"page"`
 	Limit      int         `json:"limit"`
 	TotalPages int         `json:"totalPages"`
+
+	// Set in cursor mode only, where Total and TotalPages are not computed.
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+type CategoryNode struct {
+	*sharedDomain.Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// ReconcileReport summarizes a reconciliation. Pushed and Deleted count the
+// saves and deletes queued for the Book service.
+type ReconcileReport struct {
+	DryRun      bool                   `json:"dryRun"`
+	Force       bool                   `json:"force"`
+	StartedAt   time.Time              `json:"startedAt"`
+	FinishedAt  time.Time              `json:"finishedAt"`
+	Checked     int                    `json:"checked"`
+	Unchanged   int                    `json:"unchanged"`
+	Pushed      int                    `json:"pushed"`
+	Deleted     int                    `json:"deleted"`
+	Differences []*ReconcileDifference `json:"differences"`
+}
+
+const (
+	ReconcileReasonNeverSent  = "never_sent"
+	ReconcileReasonOutdated   = "outdated"
+	ReconcileReasonNotDeleted = "not_deleted"
+	ReconcileReasonForced     = "forced"
+)
+
+// ReconcileDifference is a category whose state differs from what the Book
+// service was last sent, and the operation that repairs it.
+type ReconcileDifference struct {
+	ID        uint   `json:"id"`
+	Operation string `json:"operation"`
+	Reason    string `json:"reason"`
+}
+
+const (
+	ReconcileStatusRunning   = "running"
+	ReconcileStatusSucceeded = "succeeded"
+	ReconcileStatusFailed    = "failed"
+)
+
+// ReconcileRun is a reconciliation started in the background.
+type ReconcileRun struct {
+	ID         string           `json:"id"`
+	Status     string           `json:"status"`
+	StartedAt  time.Time        `json:"startedAt"`
+	FinishedAt *time.Time       `json:"finishedAt,omitempty"`
+	Report     *ReconcileReport `json:"report,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+const (
+	BatchStatusSucceeded = "succeeded"
+	BatchStatusFailed    = "failed"
+	BatchStatusSkipped   = "skipped"
+)
+
+type BatchCategoryResponse struct {
+	Mode      string                 `json:"mode"`
+	Committed bool                   `json:"committed"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []*BatchCategoryResult `json:"results"`
+}
+
+type BatchCategoryResult struct {
+	Index    int                    `json:"index"`
+	Op       string                 `json:"op"`
+	Status   string                 `json:"status"`
+	Category *sharedDomain.Category `json:"category,omitempty"`
+	Error    *ItemError             `json:"error,omitempty"`
+}
+
+// ItemError describes why a single item of a bulk request failed.
+type ItemError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
@@ -1,8 +1,21 @@
 package domain
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type TokenClaims struct {
-	UserID uint `json:"userId"`
+	UserID uint     `json:"userId"`
+	Roles  []string `json:"roles,omitempty"`
+	// Scope holds space-separated permissions granted to the token directly,
+	// as in OAuth 2.0.
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// Scopes returns the permissions listed in Scope.
+func (c *TokenClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
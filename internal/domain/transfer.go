@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+const (
+	TransferFormatCSV    = "csv"
+	TransferFormatNDJSON = "ndjson"
+
+	ImportMatchName = "name"
+	ImportMatchID   = "id"
+
+	ImportStatusCreated   = "created"
+	ImportStatusUpdated   = "updated"
+	ImportStatusUnchanged = "unchanged"
+)
+
+// CategoryExportRow is one exported category. The parent is exported by id
+// and by name, so an import into another environment can resolve it by name.
+type CategoryExportRow struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	ParentID   *uint     `json:"parentId"`
+	ParentName *string   `json:"parentName"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type CategoryImportRow struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	ParentID   *uint   `json:"parentId"`
+	ParentName *string `json:"parentName"`
+}
+
+// CategoryImportSource yields the rows of an import file. Next returns io.EOF
+// after the last row. A malformed row is reported as a *Error together with
+// its line number, after which reading continues; any other error aborts the
+// import.
+type CategoryImportSource interface {
+	Next() (*CategoryImportRow, int, error)
+}
+
+type ImportCategoriesRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+	Match  string `form:"match" binding:"omitempty,oneof=name id"`
+	DryRun bool   `form:"dryRun"`
+}
+
+type ExportCategoriesRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+}
+
+type ImportReport struct {
+	DryRun    bool               `json:"dryRun"`
+	Match     string             `json:"match"`
+	Total     int                `json:"total"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	Errors    []*ImportLineError `json:"errors"`
+}
+
+type ImportLineError struct {
+	Line int `json:"line"`
+	ItemError
+}
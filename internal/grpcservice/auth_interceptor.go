@@ -0,0 +1,73 @@
+package grpcservice
+
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
+	"category-service/pkg/token"
+	protoCategory "category-service/proto/category"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type claimsContextKey struct{}
+
+// JWTUnaryInterceptor requires a bearer token in the "authorization" metadata,
+// mirroring middleware.JWTAuthMiddleware for the HTTP API.
+func JWTUnaryInterceptor(tokenService token.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+		}
+
+		parts := strings.Split(values[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid token format")
+		}
+
+		tokenClaims, err := tokenService.ValidateToken(ctx, parts[1])
+		if err != nil {
+			reason := token.RejectionReason(err)
+			return nil, status.Errorf(codes.Unauthenticated, "%s (%s)", strings.ToLower(token.RejectionMessage(reason)), reason)
+		}
+
+		ctx = context.WithValue(ctx, claimsContextKey{}, tokenClaims)
+		ctx = addLogFields(ctx, logger.Fields{"user_id": tokenClaims.UserID})
+		return handler(domain.WithActor(ctx, tokenClaims.UserID), req)
+	}
+}
+
+// methodPermissions lists the permission each CategoryService method needs.
+var methodPermissions = map[string]string{
+	protoCategory.CategoryService_GetCategory_FullMethodName:        domain.PermissionCategoriesRead,
+	protoCategory.CategoryService_ListCategories_FullMethodName:     domain.PermissionCategoriesRead,
+	protoCategory.CategoryService_BatchGetCategories_FullMethodName: domain.PermissionCategoriesRead,
+	protoCategory.CategoryService_CreateCategory_FullMethodName:     domain.PermissionCategoriesWrite,
+	protoCategory.CategoryService_UpdateCategory_FullMethodName:     domain.PermissionCategoriesWrite,
+	protoCategory.CategoryService_DeleteCategory_FullMethodName:     domain.PermissionCategoriesWrite,
+}
+
+// PermissionUnaryInterceptor applies the HTTP route permissions to the gRPC
+// methods. It must be chained after JWTUnaryInterceptor; methods without an
+// entry in methodPermissions are denied.
+func PermissionUnaryInterceptor(rolePermissions domain.RolePermissions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		permission, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "method is not allowed")
+		}
+
+		claims, _ := ctx.Value(claimsContextKey{}).(*domain.TokenClaims)
+		if claims == nil || !rolePermissions.Grant(claims).Has(permission) {
+			return nil, status.Errorf(codes.PermissionDenied, "missing permission %s", permission)
+		}
+
+		return handler(ctx, req)
+	}
+}
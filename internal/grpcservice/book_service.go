@@ -1,25 +1,69 @@
 package grpcservice
 
 import (
+	"category-service/internal/domain"
 	"category-service/proto/book"
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 )
 
+// ErrBatchNotSupported is returned by the batch methods when the Book service
+// is too old to offer them; callers should fall back to single calls.
+var ErrBatchNotSupported = errors.New("book service does not support batch calls")
+
+// BookClient is the part of the Book service that category changes are
+// delivered to.
+type BookClient interface {
+	SaveCategory(ctx context.Context, req *book.CategoryData) (*book.BookResponse, error)
+	DeleteCategory(ctx context.Context, categoryId uint) (*book.BookResponse, error)
+	SaveCategories(ctx context.Context, categories []*book.CategoryData) (*book.BookResponse, error)
+	DeleteCategories(ctx context.Context, categoryIds []uint) (*book.BookResponse, error)
+}
+
 type BookGRPCClient struct {
+	conn   *grpc.ClientConn
 	client book.BookServiceClient
 }
 
-func NewBookGRPCClient(bookServiceAddr string) *BookGRPCClient {
-	conn, err := grpc.Dial(bookServiceAddr, grpc.WithInsecure())
+// NewBookGRPCClient connects to the Book service at bookServiceAddr. opts are
+// added to the defaults, e.g. keepalive parameters.
+func NewBookGRPCClient(bookServiceAddr string, opts ...grpc.DialOption) *BookGRPCClient {
+	opts = append([]grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(bookClientTracingInterceptor, bookClientUnaryInterceptor),
+	}, opts...)
+	conn, err := grpc.Dial(bookServiceAddr, opts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to Book Service: %v", err)
 	}
 
 	client := book.NewBookServiceClient(conn)
-	return &BookGRPCClient{client: client}
+	return &BookGRPCClient{conn: conn, client: client}
+}
+
+// Ping waits until the connection to the Book service is ready, connecting
+// first if it is idle, and fails with its state if that does not happen
+// before ctx is done.
+func (c *BookGRPCClient) Ping(ctx context.Context) error {
+	state := c.conn.GetState()
+	if state == connectivity.Idle {
+		c.conn.Connect()
+	}
+	for state != connectivity.Ready {
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection is %s", strings.ToLower(state.String()))
+		}
+		state = c.conn.GetState()
+	}
+	return nil
 }
 
 func (c *BookGRPCClient) SaveCategory(ctx context.Context, req *book.CategoryData) (*book.BookResponse, error) {
@@ -28,7 +72,7 @@ func (c *BookGRPCClient) SaveCategory(ctx context.Context, req *book.CategoryDat
 		return &book.BookResponse{
 			Success: false,
 			Message: err.Error(),
-		}, err
+		}, translateBookError(err)
 	}
 
 	return res, nil
@@ -41,8 +85,64 @@ func (c *BookGRPCClient) DeleteCategory(ctx context.Context, categoryId uint) (*
 		return &book.BookResponse{
 			Success: false,
 			Message: err.Error(),
-		}, err
+		}, translateBookError(err)
+	}
+
+	return res, nil
+}
+
+// SaveCategories sends several categories in one call. It returns
+// ErrBatchNotSupported if the Book service does not implement batching.
+func (c *BookGRPCClient) SaveCategories(ctx context.Context, categories []*book.CategoryData) (*book.BookResponse, error) {
+	res, err := c.client.ReceiveCategories(ctx, &book.CategoryBatch{Categories: categories})
+	if status.Code(err) == codes.Unimplemented {
+		return &book.BookResponse{
+			Success: false,
+			Message: err.Error(),
+		}, ErrBatchNotSupported
+	}
+	if err != nil {
+		return &book.BookResponse{
+			Success: false,
+			Message: err.Error(),
+		}, translateBookError(err)
 	}
 
 	return res, nil
 }
+
+// DeleteCategories deletes several categories in one call. It returns
+// ErrBatchNotSupported if the Book service does not implement batching.
+func (c *BookGRPCClient) DeleteCategories(ctx context.Context, categoryIds []uint) (*book.BookResponse, error) {
+	req := &book.DeleteBatch{Ids: make([]int64, 0, len(categoryIds))}
+	for _, id := range categoryIds {
+		req.Ids = append(req.Ids, int64(id))
+	}
+
+	res, err := c.client.DeleteCategories(ctx, req)
+	if status.Code(err) == codes.Unimplemented {
+		return &book.BookResponse{
+			Success: false,
+			Message: err.Error(),
+		}, ErrBatchNotSupported
+	}
+	if err != nil {
+		return &book.BookResponse{
+			Success: false,
+			Message: err.Error(),
+		}, translateBookError(err)
+	}
+
+	return res, nil
+}
+
+// translateBookError reports transport failures of the Book service as
+// domain.ErrBookServiceUnavailable, keeping the gRPC error as the cause.
+func translateBookError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return domain.ErrBookServiceUnavailable.Wrap(err)
+	default:
+		return err
+	}
+}
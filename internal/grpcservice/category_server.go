@@ -0,0 +1,261 @@
+package grpcservice
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/usecase"
+	sharedDomain "category-service/pkg/shared/domain"
+	protoCategory "category-service/proto/category"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultListLimit = 10
+	maxListLimit     = 100
+	maxBatchGetIDs   = 100
+)
+
+// CategoryGRPCServer exposes the category usecase to other services over gRPC.
+type CategoryGRPCServer struct {
+	protoCategory.UnimplementedCategoryServiceServer
+	usecase usecase.CategoryUsecase
+}
+
+func NewCategoryGRPCServer(uc usecase.CategoryUsecase) *CategoryGRPCServer {
+	return &CategoryGRPCServer{usecase: uc}
+}
+
+func (s *CategoryGRPCServer) GetCategory(ctx context.Context, req *protoCategory.GetCategoryRequest) (*protoCategory.CategoryResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+
+	category, err := s.usecase.GetCategoryByID(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &protoCategory.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+func (s *CategoryGRPCServer) ListCategories(ctx context.Context, req *protoCategory.ListCategoriesRequest) (*protoCategory.ListCategoriesResponse, error) {
+	page, limit := int(req.GetPage()), int(req.GetLimit())
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if page < 1 || limit < 1 || limit > maxListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "page must be at least 1 and limit between 1 and %d", maxListLimit)
+	}
+
+	result, err := s.usecase.GetAllCategories(ctx, &domain.PaginationRequest{Page: page, Limit: limit})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	categories, _ := result.Data.([]*sharedDomain.Category)
+
+	return &protoCategory.ListCategoriesResponse{
+		Categories:  toProtoCategories(categories),
+		TotalItems:  result.Total,
+		TotalPages:  int32(result.TotalPages),
+		CurrentPage: int32(page),
+		PageSize:    int32(limit),
+	}, nil
+}
+
+func (s *CategoryGRPCServer) BatchGetCategories(ctx context.Context, req *protoCategory.BatchGetCategoriesRequest) (*protoCategory.BatchGetCategoriesResponse, error) {
+	if len(req.GetIds()) > maxBatchGetIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids can be requested at once", maxBatchGetIDs)
+	}
+
+	ids := make([]uint, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		if id <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "ids must be positive")
+		}
+		ids = append(ids, uint(id))
+	}
+
+	categories, err := s.usecase.GetCategoriesByIDs(ctx, ids)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	found := make(map[int64]bool, len(categories))
+	for _, category := range categories {
+		found[int64(category.ID)] = true
+	}
+
+	var missing []int64
+	for _, id := range req.GetIds() {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return &protoCategory.BatchGetCategoriesResponse{
+		Categories: toProtoCategories(categories),
+		MissingIds: missing,
+	}, nil
+}
+
+func (s *CategoryGRPCServer) CreateCategory(ctx context.Context, req *protoCategory.CreateCategoryRequest) (*protoCategory.CategoryResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	parentID, err := toParentID(req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &domain.CreateCategoryRequest{Name: req.GetName(), ParentID: parentID}
+
+	category, err := s.usecase.CreateCategory(ctx, createReq)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &protoCategory.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+func (s *CategoryGRPCServer) UpdateCategory(ctx context.Context, req *protoCategory.UpdateCategoryRequest) (*protoCategory.CategoryResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+	if req.ParentId != nil && req.GetClearParent() {
+		return nil, status.Error(codes.InvalidArgument, "parent_id and clear_parent are mutually exclusive")
+	}
+
+	expectedVersion, err := toExpectedVersion(req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := toParentID(req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &domain.UpdateCategoryRequest{ID: uint(req.GetId()), Name: req.Name, ExpectedVersion: expectedVersion}
+	if parentID != nil {
+		updateReq.ParentID = domain.OptionalUint{Set: true, Value: parentID}
+	} else if req.GetClearParent() {
+		updateReq.ParentID = domain.OptionalUint{Set: true}
+	}
+
+	category, err := s.usecase.UpdateCategory(ctx, updateReq)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &protoCategory.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+func (s *CategoryGRPCServer) DeleteCategory(ctx context.Context, req *protoCategory.DeleteCategoryRequest) (*protoCategory.DeleteCategoryResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+
+	expectedVersion, err := toExpectedVersion(req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteReq := &domain.DeleteCategoryRequest{ID: uint(req.GetId()), ExpectedVersion: expectedVersion}
+
+	if err := s.usecase.DeleteCategory(ctx, deleteReq); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &protoCategory.DeleteCategoryResponse{Success: true, Message: "Category deleted successfully"}, nil
+}
+
+func toProtoCategory(category *sharedDomain.Category) *protoCategory.Category {
+	res := &protoCategory.Category{
+		Id:        int64(category.ID),
+		Name:      category.Name,
+		Slug:      category.Slug,
+		Version:   int64(category.Version),
+		CreatedAt: timestamppb.New(category.CreatedAt),
+		UpdatedAt: timestamppb.New(category.UpdatedAt),
+	}
+	if category.ParentID != nil {
+		parentID := int64(*category.ParentID)
+		res.ParentId = &parentID
+	}
+	return res
+}
+
+func toProtoCategories(categories []*sharedDomain.Category) []*protoCategory.Category {
+	res := make([]*protoCategory.Category, 0, len(categories))
+	for _, category := range categories {
+		res = append(res, toProtoCategory(category))
+	}
+	return res
+}
+
+// statusCodes maps domain error kinds to gRPC status codes.
+var statusCodes = map[domain.ErrorKind]codes.Code{
+	domain.KindNotFound:            codes.NotFound,
+	domain.KindConflict:            codes.AlreadyExists,
+	domain.KindValidation:          codes.InvalidArgument,
+	domain.KindUpstreamUnavailable: codes.Unavailable,
+	domain.KindPreconditionFailed:  codes.Aborted,
+}
+
+// toStatusError maps usecase errors to gRPC status codes. The domain error
+// code is sent as the status message prefix so clients can match on it.
+func toStatusError(err error) error {
+	if domainErr, ok := domain.AsError(err); ok {
+		code, ok := statusCodes[domainErr.Kind]
+		if !ok {
+			code = codes.Internal
+		}
+		if errors.Is(err, domain.ErrCategoryHasChildren) {
+			code = codes.FailedPrecondition
+		}
+		return status.Errorf(code, "%s: %s", domainErr.Code, domainErr.Message)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+// toExpectedVersion converts the optional expected_version of a request,
+// rejecting negative values instead of wrapping them around.
+func toExpectedVersion(version *int64) (*uint, error) {
+	if version == nil {
+		return nil, nil
+	}
+	if *version < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expected_version must not be negative")
+	}
+	expected := uint(*version)
+	return &expected, nil
+}
+
+// toParentID converts the optional parent_id of a request, rejecting values
+// that cannot be category IDs instead of wrapping them around.
+func toParentID(id *int64) (*uint, error) {
+	if id == nil {
+		return nil, nil
+	}
+	if *id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "parent_id must be positive")
+	}
+	parentID := uint(*id)
+	return &parentID, nil
+}
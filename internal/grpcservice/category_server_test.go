@@ -0,0 +1,68 @@
+package grpcservice
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToExpectedVersion(t *testing.T) {
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name     string
+		version  *int64
+		want     *uint
+		wantCode codes.Code
+	}{
+		{name: "unset", version: nil},
+		{name: "zero", version: version(0), want: new(uint)},
+		{name: "positive", version: version(7), want: func() *uint { v := uint(7); return &v }()},
+		{name: "negative", version: version(-1), wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toExpectedVersion(tt.version)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("toExpectedVersion() error = %v, want code %s", err, tt.wantCode)
+			}
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || *got != *tt.want:
+				t.Errorf("toExpectedVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToParentID(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name     string
+		id       *int64
+		want     *uint
+		wantCode codes.Code
+	}{
+		{name: "unset", id: nil},
+		{name: "positive", id: id(7), want: func() *uint { v := uint(7); return &v }()},
+		{name: "zero", id: id(0), wantCode: codes.InvalidArgument},
+		{name: "negative", id: id(-1), wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toParentID(tt.id)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("toParentID() error = %v, want code %s", err, tt.wantCode)
+			}
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || *got != *tt.want:
+				t.Errorf("toParentID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package grpcservice
+
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
+	"category-service/pkg/metrics"
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadataKey carries the request ID between services, mirroring
+// the X-Request-ID header of the HTTP API.
+const requestIDMetadataKey = "x-request-id"
+
+// RequestLoggingUnaryInterceptor assigns the request ID and a request-scoped
+// logger like middleware.RequestIDMiddleware, and logs every call once it
+// completed. It must be the first interceptor of the chain.
+func RequestLoggingUnaryInterceptor(base logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		var incoming string
+		if values := metadata.ValueFromIncomingContext(ctx, requestIDMetadataKey); len(values) > 0 {
+			incoming = values[0]
+		}
+		requestID := domain.EnsureRequestID(incoming)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+
+		// The logger is stored in a holder so that fields added further down
+		// the chain, such as the user ID, show up in the completion entry.
+		holder := &loggerHolder{logger: base.With(logger.Fields{
+			"request_id": requestID,
+			"route":      info.FullMethod,
+		})}
+		ctx = domain.WithRequestID(ctx, requestID)
+		ctx = logger.WithContext(ctx, holder.logger)
+		ctx = context.WithValue(ctx, loggerHolderKey{}, holder)
+
+		res, err := handler(ctx, req)
+
+		code := status.Code(err)
+		log := holder.logger.With(logger.Fields{
+			"code":        code.String(),
+			"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+		})
+		message := fmt.Sprintf("%s %s", info.FullMethod, code)
+		switch code {
+		case codes.OK:
+			log.Info(message, "grpc_request", info.FullMethod)
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			log.Error(message+": "+status.Convert(err).Message(), "grpc_request", info.FullMethod)
+		default:
+			log.Warn(message+": "+status.Convert(err).Message(), "grpc_request", info.FullMethod)
+		}
+		return res, err
+	}
+}
+
+type loggerHolderKey struct{}
+
+type loggerHolder struct {
+	logger logger.Logger
+}
+
+// addLogFields adds fields to the request-scoped logger of ctx, including the
+// one RequestLoggingUnaryInterceptor logs the completed call with.
+func addLogFields(ctx context.Context, fields logger.Fields) context.Context {
+	ctx = logger.AddFields(ctx, fields)
+	if holder, ok := ctx.Value(loggerHolderKey{}).(*loggerHolder); ok {
+		holder.logger = logger.FromContext(ctx)
+	}
+	return ctx
+}
+
+// bookClientUnaryInterceptor forwards the request ID to the Book service and
+// logs and measures the outcome and latency of every call.
+func bookClientUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if requestID := domain.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, requestID)
+	}
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	elapsed := time.Since(start)
+
+	code := status.Code(err).String()
+	metrics.ObserveBookCall(path.Base(method), code, elapsed)
+
+	log := logger.FromContext(ctx).With(logger.Fields{
+		"rpc":         method,
+		"code":        code,
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+	})
+	if err != nil {
+		log.Warn(fmt.Sprintf("Book service call %s failed: %v", method, err), "book_client", method)
+	} else {
+		log.Debug("Book service call "+method+" succeeded", "book_client", method)
+	}
+	return err
+}
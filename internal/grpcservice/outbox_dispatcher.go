@@ -0,0 +1,327 @@
+package grpcservice
+
+import (
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/tracing"
+	"category-service/proto/book"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type OutboxDispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// AlertAfterAttempts is the number of failed attempts after which an
+	// event is logged as an error instead of a warning. Events are never
+	// given up on, because skipping one would deliver later changes of the
+	// same category without it.
+	AlertAfterAttempts int
+	BaseBackoff        time.Duration
+	MaxBackoff         time.Duration
+	CallTimeout        time.Duration
+	// LeaseTimeout is how long claimed events are reserved for this
+	// dispatcher. It is raised to BatchSize × CallTimeout if that is longer,
+	// so that a batch can be delivered before its lease expires even with
+	// single calls.
+	LeaseTimeout time.Duration
+	// BatchProbeInterval is how long single calls are made after the Book
+	// service rejected a batch call as unimplemented, before batching is
+	// tried again.
+	BatchProbeInterval time.Duration
+}
+
+// DefaultOutboxDispatcherConfig returns the settings used when none are configured.
+func DefaultOutboxDispatcherConfig() OutboxDispatcherConfig {
+	return OutboxDispatcherConfig{
+		PollInterval:       2 * time.Second,
+		BatchSize:          50,
+		AlertAfterAttempts: 10,
+		BaseBackoff:        time.Second,
+		MaxBackoff:         5 * time.Minute,
+		CallTimeout:        5 * time.Second,
+		LeaseTimeout:       time.Minute,
+		BatchProbeInterval: 10 * time.Minute,
+	}
+}
+
+// OutboxDispatcher delivers outbox events to the Book service. An event is
+// only marked as sent after the Book service acknowledged it, so delivery is
+// at-least-once and the Book service must treat events idempotently.
+type OutboxDispatcher struct {
+	repo       repository.OutboxRepository
+	bookClient BookClient
+	logger     logger.Logger
+	cfg        OutboxDispatcherConfig
+
+	// batchUnsupportedUntil is the time, in Unix nanoseconds, until which
+	// single calls are made instead of batch calls.
+	batchUnsupportedUntil atomic.Int64
+}
+
+func NewOutboxDispatcher(repo repository.OutboxRepository, bookClient BookClient, logger logger.Logger, cfg OutboxDispatcherConfig) *OutboxDispatcher {
+	return &OutboxDispatcher{repo: repo, bookClient: bookClient, logger: logger, cfg: cfg}
+}
+
+// Run polls for pending events until ctx is cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		// PostgreSQL stores microseconds, so the lease is truncated to match
+		// the stored value when the outcome is recorded.
+		lockedUntil := time.Now().Add(d.lease()).Truncate(time.Microsecond)
+		events, err := d.repo.ClaimPendingEvents(ctx, d.cfg.BatchSize, lockedUntil)
+		if err != nil {
+			d.logger.Error(fmt.Sprintf("Failed to claim outbox events: %v", err), "outbox", "claim")
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		d.dispatchBatch(ctx, events, lockedUntil)
+
+		if len(events) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// lease returns how long claimed events are reserved for this dispatcher.
+func (d *OutboxDispatcher) lease() time.Duration {
+	return max(d.cfg.LeaseTimeout, time.Duration(d.cfg.BatchSize)*d.cfg.CallTimeout)
+}
+
+// decodedEvent is an outbox event together with its parsed payload.
+type decodedEvent struct {
+	event   *sharedDomain.OutboxEvent
+	payload sharedDomain.CategoryEventPayload
+}
+
+// dispatchBatch delivers claimed events, combining saves and deletes into one
+// Book service call each. A claimed batch holds at most one event per
+// category, so the calls can be reordered without breaking per-category order.
+func (d *OutboxDispatcher) dispatchBatch(ctx context.Context, events []*sharedDomain.OutboxEvent, lockedUntil time.Time) {
+	var saves, deletes []decodedEvent
+	for _, event := range events {
+		var payload sharedDomain.CategoryEventPayload
+		if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+			d.record(ctx, event, lockedUntil, fmt.Errorf("invalid payload: %w", err))
+			continue
+		}
+
+		switch event.EventType {
+		case sharedDomain.OutboxEventCategorySaved:
+			saves = append(saves, decodedEvent{event: event, payload: payload})
+		case sharedDomain.OutboxEventCategoryDeleted:
+			deletes = append(deletes, decodedEvent{event: event, payload: payload})
+		default:
+			d.record(ctx, event, lockedUntil, fmt.Errorf("unknown event type %q", event.EventType))
+		}
+	}
+
+	if d.deliverGroup(ctx, saves, lockedUntil, d.sendSaves, d.sendSave) {
+		d.deliverGroup(ctx, deletes, lockedUntil, d.sendDeletes, d.sendDelete)
+	}
+}
+
+// deliverGroup delivers events of one type in a batch call, or in single
+// calls while the Book service does not support batching. It returns false
+// if it stopped because the lease is about to expire.
+func (d *OutboxDispatcher) deliverGroup(
+	ctx context.Context,
+	group []decodedEvent,
+	lockedUntil time.Time,
+	sendBatch func(ctx context.Context, group []decodedEvent) (*book.BookResponse, error),
+	sendOne func(ctx context.Context, item decodedEvent) (*book.BookResponse, error),
+) bool {
+	if len(group) == 0 {
+		return true
+	}
+
+	if len(group) > 1 && time.Now().UnixNano() >= d.batchUnsupportedUntil.Load() {
+		if !d.leaseLeft(lockedUntil) {
+			return false
+		}
+		spanCtx, span := startDeliverySpan(ctx, group)
+		res, err := d.call(spanCtx, lockedUntil, func(callCtx context.Context) (*book.BookResponse, error) {
+			return sendBatch(callCtx, group)
+		})
+		if !errors.Is(err, ErrBatchNotSupported) {
+			err = checkBookResponse(res, err)
+			tracing.RecordError(span, err)
+			span.End()
+			for _, item := range group {
+				d.record(ctx, item.event, lockedUntil, err)
+			}
+			return true
+		}
+		span.End()
+
+		d.batchUnsupportedUntil.Store(time.Now().Add(d.cfg.BatchProbeInterval).UnixNano())
+		d.logger.Warn(fmt.Sprintf("Book service does not support batch calls, making single calls for %s", d.cfg.BatchProbeInterval), "outbox", "batch")
+	}
+
+	for _, item := range group {
+		if !d.leaseLeft(lockedUntil) {
+			return false
+		}
+		spanCtx, span := startDeliverySpan(ctx, []decodedEvent{item})
+		res, err := d.call(spanCtx, lockedUntil, func(callCtx context.Context) (*book.BookResponse, error) {
+			return sendOne(callCtx, item)
+		})
+		err = checkBookResponse(res, err)
+		tracing.RecordError(span, err)
+		span.End()
+		d.record(ctx, item.event, lockedUntil, err)
+	}
+	return true
+}
+
+// leaseLeft reports whether a call can still end before the lease does. Once
+// the lease expires, another dispatcher may claim the remaining events and
+// deliver newer changes of their categories, so they are left to it.
+func (d *OutboxDispatcher) leaseLeft(lockedUntil time.Time) bool {
+	if time.Until(lockedUntil) >= d.cfg.CallTimeout {
+		return true
+	}
+	d.logger.Warn("Outbox lease is about to expire, leaving the remaining events to the next claim", "outbox", "lease")
+	return false
+}
+
+// startDeliverySpan starts the root span of delivering group, linked to the
+// requests that made the changes.
+func startDeliverySpan(ctx context.Context, group []decodedEvent) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(group))
+	for _, item := range group {
+		if link, ok := tracing.LinkTo(item.event.TraceParent); ok {
+			links = append(links, link)
+		}
+	}
+
+	return tracer.Start(ctx, "outbox.deliver "+group[0].event.EventType,
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("outbox.events", len(group))),
+	)
+}
+
+// call runs fn with the call timeout, ending no later than the lease.
+func (d *OutboxDispatcher) call(ctx context.Context, lockedUntil time.Time, fn func(callCtx context.Context) (*book.BookResponse, error)) (*book.BookResponse, error) {
+	deadline := time.Now().Add(d.cfg.CallTimeout)
+	if lockedUntil.Before(deadline) {
+		deadline = lockedUntil
+	}
+	callCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	return fn(callCtx)
+}
+
+func (d *OutboxDispatcher) sendSaves(ctx context.Context, group []decodedEvent) (*book.BookResponse, error) {
+	categories := make([]*book.CategoryData, 0, len(group))
+	for _, item := range group {
+		categories = append(categories, toCategoryData(item.payload))
+	}
+	return d.bookClient.SaveCategories(ctx, categories)
+}
+
+func (d *OutboxDispatcher) sendSave(ctx context.Context, item decodedEvent) (*book.BookResponse, error) {
+	return d.bookClient.SaveCategory(ctx, toCategoryData(item.payload))
+}
+
+func (d *OutboxDispatcher) sendDeletes(ctx context.Context, group []decodedEvent) (*book.BookResponse, error) {
+	ids := make([]uint, 0, len(group))
+	for _, item := range group {
+		ids = append(ids, item.payload.ID)
+	}
+	return d.bookClient.DeleteCategories(ctx, ids)
+}
+
+func (d *OutboxDispatcher) sendDelete(ctx context.Context, item decodedEvent) (*book.BookResponse, error) {
+	return d.bookClient.DeleteCategory(ctx, item.payload.ID)
+}
+
+// record stores the outcome of delivering event: sent on success, otherwise
+// rescheduled with backoff. Failing events are retried at MaxBackoff for as
+// long as it takes and keep holding back later events of their category.
+func (d *OutboxDispatcher) record(ctx context.Context, event *sharedDomain.OutboxEvent, lockedUntil time.Time, err error) {
+	key := fmt.Sprintf("event:%d", event.ID)
+
+	// The event status must be recorded even if ctx was cancelled during
+	// delivery, otherwise the event stays leased until the lease expires.
+	storeCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		held, err := d.repo.MarkEventSent(storeCtx, event.ID, lockedUntil)
+		if err != nil {
+			d.logger.Error(fmt.Sprintf("Failed to mark outbox event as sent: %v", err), "outbox", key)
+		} else if !held {
+			d.logger.Warn("Outbox event was delivered after its lease expired; it will be delivered again", "outbox", key)
+		}
+		return
+	}
+
+	attempts := event.Attempts + 1
+	nextAttemptAt := time.Now().Add(d.backoff(attempts))
+	message := fmt.Sprintf("Outbox event %s failed (attempt %d), retrying at %s: %v", event.EventType, attempts, nextAttemptAt.Format(time.RFC3339), err)
+	if attempts >= d.cfg.AlertAfterAttempts {
+		d.logger.Error(message, "outbox", key)
+	} else {
+		d.logger.Warn(message, "outbox", key)
+	}
+	held, err := d.repo.MarkEventRetry(storeCtx, event.ID, lockedUntil, attempts, nextAttemptAt, err.Error())
+	if err != nil {
+		d.logger.Error(fmt.Sprintf("Failed to reschedule outbox event: %v", err), "outbox", key)
+	} else if !held {
+		d.logger.Warn("Outbox event lease expired before its failure was recorded", "outbox", key)
+	}
+}
+
+func toCategoryData(payload sharedDomain.CategoryEventPayload) *book.CategoryData {
+	return &book.CategoryData{Id: int64(payload.ID), Name: payload.Name, Slug: payload.Slug}
+}
+
+// checkBookResponse turns a response the Book service did not accept into an error.
+func checkBookResponse(res *book.BookResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	if res != nil && !res.Success {
+		return fmt.Errorf("book service rejected event: %s", res.Message)
+	}
+	return nil
+}
+
+// backoff returns the exponential delay before the given attempt, capped at MaxBackoff.
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
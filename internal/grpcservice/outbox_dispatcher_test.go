@@ -0,0 +1,405 @@
+package grpcservice
+
+import (
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/proto/book"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeOutboxRepository struct {
+	batches [][]*sharedDomain.OutboxEvent
+
+	// leaseLost makes the marks report that the lease has expired.
+	leaseLost bool
+	lastSent  map[uint]*sharedDomain.OutboxEvent
+
+	claimLimits []int
+	claimLeases []time.Time
+	sent        []uint
+	retries     []outboxRetry
+}
+
+type outboxRetry struct {
+	id            uint
+	attempts      int
+	nextAttemptAt time.Time
+}
+
+func (r *fakeOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lockedUntil time.Time) ([]*sharedDomain.OutboxEvent, error) {
+	r.claimLimits = append(r.claimLimits, limit)
+	r.claimLeases = append(r.claimLeases, lockedUntil)
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *fakeOutboxRepository) MarkEventSent(ctx context.Context, id uint, lockedUntil time.Time) (bool, error) {
+	if r.leaseLost {
+		return false, nil
+	}
+	r.sent = append(r.sent, id)
+	return true, nil
+}
+
+func (r *fakeOutboxRepository) MarkEventRetry(ctx context.Context, id uint, lockedUntil time.Time, attempts int, nextAttemptAt time.Time, lastError string) (bool, error) {
+	if r.leaseLost {
+		return false, nil
+	}
+	r.retries = append(r.retries, outboxRetry{id: id, attempts: attempts, nextAttemptAt: nextAttemptAt})
+	return true, nil
+}
+
+func (r *fakeOutboxRepository) DeleteSentEvents(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeOutboxRepository) GetLastSentEvents(ctx context.Context, categoryIDs []uint) (map[uint]*sharedDomain.OutboxEvent, error) {
+	return r.lastSent, nil
+}
+
+func (r *fakeOutboxRepository) CountPendingEvents(ctx context.Context) (int64, time.Time, error) {
+	return 0, time.Time{}, nil
+}
+
+// fakeBookClient records the calls made to the Book service. Each accepted
+// call takes delay.
+type fakeBookClient struct {
+	delay            time.Duration
+	batchUnsupported bool
+	calls            []string
+}
+
+func (c *fakeBookClient) SaveCategory(ctx context.Context, req *book.CategoryData) (*book.BookResponse, error) {
+	c.calls = append(c.calls, fmt.Sprintf("save %d", req.Id))
+	time.Sleep(c.delay)
+	return &book.BookResponse{Success: true}, nil
+}
+
+func (c *fakeBookClient) DeleteCategory(ctx context.Context, categoryId uint) (*book.BookResponse, error) {
+	c.calls = append(c.calls, fmt.Sprintf("delete %d", categoryId))
+	time.Sleep(c.delay)
+	return &book.BookResponse{Success: true}, nil
+}
+
+func (c *fakeBookClient) SaveCategories(ctx context.Context, categories []*book.CategoryData) (*book.BookResponse, error) {
+	ids := make([]string, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, fmt.Sprint(category.Id))
+	}
+	return c.batch("save " + strings.Join(ids, ","))
+}
+
+func (c *fakeBookClient) DeleteCategories(ctx context.Context, categoryIds []uint) (*book.BookResponse, error) {
+	ids := make([]string, 0, len(categoryIds))
+	for _, id := range categoryIds {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return c.batch("delete " + strings.Join(ids, ","))
+}
+
+func (c *fakeBookClient) batch(call string) (*book.BookResponse, error) {
+	c.calls = append(c.calls, call)
+	if c.batchUnsupported {
+		return &book.BookResponse{}, ErrBatchNotSupported
+	}
+	time.Sleep(c.delay)
+	return &book.BookResponse{Success: true}, nil
+}
+
+// recordingLogger counts entries by level.
+type recordingLogger struct {
+	warnings int
+	errors   int
+}
+
+func (l *recordingLogger) Info(message, event, key string)  {}
+func (l *recordingLogger) Warn(message, event, key string)  { l.warnings++ }
+func (l *recordingLogger) Error(message, event, key string) { l.errors++ }
+func (l *recordingLogger) Debug(message, event, key string) {}
+func (l *recordingLogger) Fatal(message, event, key string) {}
+func (l *recordingLogger) Panic(message, event, key string) {}
+func (l *recordingLogger) SetOutput(output *os.File)        {}
+func (l *recordingLogger) SetFormat(format string)          {}
+func (l *recordingLogger) With(fields logger.Fields) logger.Logger {
+	return l
+}
+
+// undeliverableEvent fails delivery without reaching the Book service.
+func undeliverableEvent(id uint, attempts int) *sharedDomain.OutboxEvent {
+	return &sharedDomain.OutboxEvent{ID: id, EventType: "unknown", Payload: "{}", Attempts: attempts}
+}
+
+func savedEvent(id, categoryID uint) *sharedDomain.OutboxEvent {
+	return &sharedDomain.OutboxEvent{ID: id, AggregateID: categoryID, EventType: sharedDomain.OutboxEventCategorySaved, Payload: fmt.Sprintf(`{"id":%d,"name":"c%d"}`, categoryID, categoryID)}
+}
+
+func deletedEvent(id, categoryID uint) *sharedDomain.OutboxEvent {
+	return &sharedDomain.OutboxEvent{ID: id, AggregateID: categoryID, EventType: sharedDomain.OutboxEventCategoryDeleted, Payload: fmt.Sprintf(`{"id":%d}`, categoryID)}
+}
+
+func TestOutboxDispatcherBackoff(t *testing.T) {
+	d := &OutboxDispatcher{cfg: OutboxDispatcherConfig{BaseBackoff: time.Second, MaxBackoff: time.Minute}}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Second},
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 6, want: 32 * time.Second},
+		{attempts: 7, want: time.Minute},
+		{attempts: 1000, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxDispatcherDispatchPending(t *testing.T) {
+	cfg := OutboxDispatcherConfig{
+		BatchSize:          2,
+		AlertAfterAttempts: 3,
+		BaseBackoff:        time.Second,
+		MaxBackoff:         time.Minute,
+		CallTimeout:        time.Second,
+		LeaseTimeout:       time.Minute,
+	}
+
+	tests := []struct {
+		name         string
+		batches      [][]*sharedDomain.OutboxEvent
+		wantClaims   int
+		wantRetries  []outboxRetry
+		wantWarnings int
+		wantErrors   int
+	}{
+		{
+			name:       "no events",
+			wantClaims: 1,
+		},
+		{
+			name:         "partial batch stops claiming",
+			batches:      [][]*sharedDomain.OutboxEvent{{undeliverableEvent(1, 0)}},
+			wantClaims:   1,
+			wantRetries:  []outboxRetry{{id: 1, attempts: 1}},
+			wantWarnings: 1,
+		},
+		{
+			name: "full batch claims again",
+			batches: [][]*sharedDomain.OutboxEvent{
+				{undeliverableEvent(1, 0), undeliverableEvent(2, 0)},
+				{undeliverableEvent(3, 0)},
+			},
+			wantClaims:   2,
+			wantRetries:  []outboxRetry{{id: 1, attempts: 1}, {id: 2, attempts: 1}, {id: 3, attempts: 1}},
+			wantWarnings: 3,
+		},
+		{
+			name:        "repeated failures alert but keep retrying",
+			batches:     [][]*sharedDomain.OutboxEvent{{undeliverableEvent(1, 2)}},
+			wantClaims:  1,
+			wantRetries: []outboxRetry{{id: 1, attempts: 3}},
+			wantErrors:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepository{batches: tt.batches}
+			log := &recordingLogger{}
+			d := NewOutboxDispatcher(repo, nil, log, cfg)
+
+			start := time.Now()
+			d.dispatchPending(context.Background())
+
+			if len(repo.claimLimits) != tt.wantClaims {
+				t.Fatalf("claimed %d times, want %d", len(repo.claimLimits), tt.wantClaims)
+			}
+			for i, limit := range repo.claimLimits {
+				if limit != cfg.BatchSize {
+					t.Errorf("claim %d limit = %d, want %d", i, limit, cfg.BatchSize)
+				}
+				if lease := repo.claimLeases[i].Sub(start.Truncate(time.Microsecond)); lease < cfg.LeaseTimeout || lease > cfg.LeaseTimeout+time.Second {
+					t.Errorf("claim %d leased for %s, want %s", i, lease, cfg.LeaseTimeout)
+				}
+			}
+
+			if len(repo.sent) != 0 {
+				t.Errorf("sent %v, want none", repo.sent)
+			}
+			if len(repo.retries) != len(tt.wantRetries) {
+				t.Fatalf("retried %d events, want %d", len(repo.retries), len(tt.wantRetries))
+			}
+			for i, want := range tt.wantRetries {
+				got := repo.retries[i]
+				if got.id != want.id || got.attempts != want.attempts {
+					t.Errorf("retry %d = event %d attempt %d, want event %d attempt %d", i, got.id, got.attempts, want.id, want.attempts)
+				}
+				wantAt := start.Add(d.backoff(want.attempts))
+				if got.nextAttemptAt.Before(wantAt) || got.nextAttemptAt.After(wantAt.Add(time.Second)) {
+					t.Errorf("retry %d scheduled at %s, want %s", i, got.nextAttemptAt, wantAt)
+				}
+			}
+
+			if log.warnings != tt.wantWarnings || log.errors != tt.wantErrors {
+				t.Errorf("logged %d warnings and %d errors, want %d and %d", log.warnings, log.errors, tt.wantWarnings, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestOutboxDispatcherDelivers(t *testing.T) {
+	cfg := OutboxDispatcherConfig{
+		BatchSize:          3,
+		AlertAfterAttempts: 3,
+		BaseBackoff:        time.Second,
+		MaxBackoff:         time.Minute,
+		CallTimeout:        50 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name             string
+		batches          [][]*sharedDomain.OutboxEvent
+		delay            time.Duration
+		batchUnsupported bool
+		leaseLost        bool
+		wantCalls        []string
+		wantSent         []uint
+		wantWarnings     int
+	}{
+		{
+			// Category 20 is deleted in the first claim and saved again in
+			// the second.
+			name: "saves and deletes are batched in claim order",
+			batches: [][]*sharedDomain.OutboxEvent{
+				{savedEvent(1, 10), deletedEvent(2, 20), savedEvent(3, 30)},
+				{savedEvent(4, 20)},
+			},
+			wantCalls: []string{"save 10,30", "delete 20", "save 20"},
+			wantSent:  []uint{1, 3, 2, 4},
+		},
+		{
+			name:             "single calls without batch support",
+			batches:          [][]*sharedDomain.OutboxEvent{{savedEvent(1, 10), savedEvent(2, 20), deletedEvent(3, 30)}},
+			batchUnsupported: true,
+			wantCalls:        []string{"save 10,20", "save 10", "save 20", "delete 30"},
+			wantSent:         []uint{1, 2, 3},
+			wantWarnings:     1,
+		},
+		{
+			name:         "delivery after the lease expired is not marked sent",
+			batches:      [][]*sharedDomain.OutboxEvent{{savedEvent(1, 10)}},
+			leaseLost:    true,
+			wantCalls:    []string{"save 10"},
+			wantWarnings: 1,
+		},
+		{
+			// The batch is leased for 3 × 50ms; after two 60ms single calls
+			// less than a call timeout is left.
+			name:             "events are left when the lease runs out",
+			batches:          [][]*sharedDomain.OutboxEvent{{savedEvent(1, 10), savedEvent(2, 20), savedEvent(3, 30)}},
+			delay:            60 * time.Millisecond,
+			batchUnsupported: true,
+			wantCalls:        []string{"save 10,20,30", "save 10", "save 20"},
+			wantSent:         []uint{1, 2},
+			wantWarnings:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepository{batches: tt.batches, leaseLost: tt.leaseLost}
+			client := &fakeBookClient{delay: tt.delay, batchUnsupported: tt.batchUnsupported}
+			log := &recordingLogger{}
+			d := NewOutboxDispatcher(repo, client, log, cfg)
+
+			d.dispatchPending(context.Background())
+
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("Book calls = %v, want %v", client.calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(repo.sent, tt.wantSent) {
+				t.Errorf("sent %v, want %v", repo.sent, tt.wantSent)
+			}
+			if len(repo.retries) != 0 {
+				t.Errorf("retried %v, want none", repo.retries)
+			}
+			if log.warnings != tt.wantWarnings || log.errors != 0 {
+				t.Errorf("logged %d warnings and %d errors, want %d and none", log.warnings, log.errors, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestOutboxDispatcherProbesBatchSupport(t *testing.T) {
+	cfg := OutboxDispatcherConfig{
+		BatchSize:          3,
+		AlertAfterAttempts: 3,
+		BaseBackoff:        time.Second,
+		MaxBackoff:         time.Minute,
+		CallTimeout:        time.Second,
+		BatchProbeInterval: 50 * time.Millisecond,
+	}
+	client := &fakeBookClient{batchUnsupported: true}
+	repo := &fakeOutboxRepository{}
+	d := NewOutboxDispatcher(repo, client, &recordingLogger{}, cfg)
+
+	dispatch := func(want ...string) {
+		t.Helper()
+		client.calls = nil
+		repo.batches = [][]*sharedDomain.OutboxEvent{{savedEvent(1, 10), savedEvent(2, 20)}}
+		d.dispatchPending(context.Background())
+		if !reflect.DeepEqual(client.calls, want) {
+			t.Errorf("Book calls = %v, want %v", client.calls, want)
+		}
+	}
+
+	dispatch("save 10,20", "save 10", "save 20")
+	// The Book service gains batch support, which is only noticed once the
+	// probe interval passed.
+	client.batchUnsupported = false
+	dispatch("save 10", "save 20")
+	time.Sleep(cfg.BatchProbeInterval)
+	dispatch("save 10,20")
+}
+
+func TestOutboxDispatcherLease(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  OutboxDispatcherConfig
+		want time.Duration
+	}{
+		{
+			name: "configured lease covers the batch",
+			cfg:  OutboxDispatcherConfig{BatchSize: 10, CallTimeout: time.Second, LeaseTimeout: time.Minute},
+			want: time.Minute,
+		},
+		{
+			name: "lease grows with the batch",
+			cfg:  OutboxDispatcherConfig{BatchSize: 50, CallTimeout: 5 * time.Second, LeaseTimeout: time.Minute},
+			want: 250 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		d := &OutboxDispatcher{cfg: tt.cfg}
+		if got := d.lease(); got != tt.want {
+			t.Errorf("%s: lease() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
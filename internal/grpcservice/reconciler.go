@@ -0,0 +1,275 @@
+package grpcservice
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/tracing"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	reconcileOperationSave   = "save"
+	reconcileOperationDelete = "delete"
+)
+
+type ReconcileOptions struct {
+	DryRun bool
+	// Force pushes every category instead of only those that differ from
+	// what the Book service was last sent.
+	Force     bool
+	BatchSize int
+}
+
+// CategoryReconciler brings the Book service's copy of the catalog back in
+// line with ours after missed deliveries or data loss on its side. Repairs
+// are queued in the outbox, so they are delivered in order with the changes
+// made in the meantime.
+type CategoryReconciler struct {
+	repo       repository.CategoryRepository
+	outboxRepo repository.OutboxRepository
+	logger     logger.Logger
+
+	mu      sync.Mutex
+	lastRun *domain.ReconcileRun
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewCategoryReconciler(repo repository.CategoryRepository, outboxRepo repository.OutboxRepository, logger logger.Logger) *CategoryReconciler {
+	return &CategoryReconciler{repo: repo, outboxRepo: outboxRepo, logger: logger}
+}
+
+// Start runs Reconcile in the background and returns the new run. Only one
+// run per instance can be in progress at a time. The run keeps the values of
+// ctx, such as the request ID, but not its cancellation.
+func (r *CategoryReconciler) Start(ctx context.Context, opts ReconcileOptions) (*domain.ReconcileRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastRun != nil && r.lastRun.Status == domain.ReconcileStatusRunning {
+		return nil, domain.ErrReconcileRunning
+	}
+
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	run := &domain.ReconcileRun{ID: hex.EncodeToString(id), Status: domain.ReconcileStatusRunning, StartedAt: time.Now()}
+	r.lastRun = run
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		defer cancel()
+
+		report, err := r.Reconcile(runCtx, opts)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		run.Report = report
+		run.Status = domain.ReconcileStatusSucceeded
+		if err != nil {
+			run.Status = domain.ReconcileStatusFailed
+			run.Error = err.Error()
+			r.logger.Error(fmt.Sprintf("Reconciliation %s aborted: %v", run.ID, err), "reconcile", "error")
+		}
+	}(r.done)
+
+	copied := *run
+	return &copied, nil
+}
+
+// LastRun returns the most recently started run.
+func (r *CategoryReconciler) LastRun() (*domain.ReconcileRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastRun == nil {
+		return nil, domain.ErrReconcileNotFound
+	}
+	copied := *r.lastRun
+	return &copied, nil
+}
+
+// Stop cancels a run in progress and waits until it ended or ctx is done.
+func (r *CategoryReconciler) Stop(ctx context.Context) {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// Reconcile compares every category with the last event the Book service
+// acknowledged for it and queues a save or delete for those that differ, or
+// for all of them with opts.Force. Only database errors and cancellation stop
+// it early.
+//
+// The Book service offers no way to read its copy, so a change lost on its
+// side after it was acknowledged is only repaired with opts.Force.
+func (r *CategoryReconciler) Reconcile(ctx context.Context, opts ReconcileOptions) (*domain.ReconcileReport, error) {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+
+	report := &domain.ReconcileReport{
+		DryRun:      opts.DryRun,
+		Force:       opts.Force,
+		StartedAt:   time.Now(),
+		Differences: []*domain.ReconcileDifference{},
+	}
+	r.logger.Info(fmt.Sprintf("Reconciliation started (dry run: %t, force: %t)", opts.DryRun, opts.Force), "reconcile", "start")
+
+	// Categories are read by ID instead of by page number, so that
+	// categories created or purged during the run do not shift the pages.
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		categories, err := r.repo.GetCategoriesAfterID(ctx, lastID, opts.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]uint, 0, len(categories))
+		for _, category := range categories {
+			ids = append(ids, category.ID)
+		}
+		lastSent, err := r.outboxRepo.GetLastSentEvents(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, category := range categories {
+			if err := r.check(ctx, opts, report, category, lastSent[category.ID]); err != nil {
+				return nil, err
+			}
+			lastID = category.ID
+		}
+
+		if len(categories) < opts.BatchSize {
+			break
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	r.logger.Info(fmt.Sprintf("Reconciliation finished: %d checked, %d unchanged, %d pushed, %d deleted",
+		report.Checked, report.Unchanged, report.Pushed, report.Deleted), "reconcile", "finish")
+
+	return report, nil
+}
+
+// check records whether category differs from what the Book service was last
+// sent and, unless opts.DryRun, queues the repair.
+func (r *CategoryReconciler) check(ctx context.Context, opts ReconcileOptions, report *domain.ReconcileReport, category *sharedDomain.Category, lastSent *sharedDomain.OutboxEvent) error {
+	operation := reconcileOperationSave
+	reason := liveDifference(category, lastSent, opts.Force)
+	if category.DeletedAt.Valid {
+		operation = reconcileOperationDelete
+		reason = deletedDifference(lastSent, opts.Force)
+	}
+
+	report.Checked++
+	if reason == "" {
+		report.Unchanged++
+		return nil
+	}
+	report.Differences = append(report.Differences, &domain.ReconcileDifference{ID: category.ID, Operation: operation, Reason: reason})
+
+	if !opts.DryRun {
+		if err := r.enqueue(ctx, category.ID); err != nil {
+			return err
+		}
+	}
+
+	switch operation {
+	case reconcileOperationSave:
+		report.Pushed++
+	case reconcileOperationDelete:
+		report.Deleted++
+	}
+	return nil
+}
+
+// enqueue queues the current state of category id. The category is locked
+// while its event is written, so the event is ordered after every change
+// committed before and before every change made after it, and never
+// overwrites a newer state on the Book service.
+func (r *CategoryReconciler) enqueue(ctx context.Context, id uint) error {
+	return r.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		category, err := repo.GetCategoryForUpdate(ctx, id)
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			// Purged since it was read; the Book service was sent its
+			// deletion before.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var event *sharedDomain.OutboxEvent
+		if category.DeletedAt.Valid {
+			event, err = sharedDomain.NewCategoryDeletedEvent(category.ID, tracing.TraceParent(ctx))
+		} else {
+			event, err = sharedDomain.NewCategorySavedEvent(category, tracing.TraceParent(ctx))
+		}
+		if err != nil {
+			return err
+		}
+		return repo.SaveOutboxEvent(ctx, event)
+	})
+}
+
+// liveDifference returns why the Book service's copy of a live category is
+// out of date, or "" if the last acknowledged event matches it.
+func liveDifference(category *sharedDomain.Category, lastSent *sharedDomain.OutboxEvent, force bool) string {
+	switch {
+	case lastSent == nil:
+		return domain.ReconcileReasonNeverSent
+	case lastSent.EventType != sharedDomain.OutboxEventCategorySaved:
+		return domain.ReconcileReasonOutdated
+	}
+
+	var payload sharedDomain.CategoryEventPayload
+	if err := json.Unmarshal([]byte(lastSent.Payload), &payload); err != nil ||
+		payload.Name != category.Name || payload.Slug != category.Slug {
+		return domain.ReconcileReasonOutdated
+	}
+	if force {
+		return domain.ReconcileReasonForced
+	}
+	return ""
+}
+
+// deletedDifference returns why the Book service may still hold a deleted
+// category, or "" if it acknowledged the delete.
+func deletedDifference(lastSent *sharedDomain.OutboxEvent, force bool) string {
+	switch {
+	case lastSent == nil || lastSent.EventType != sharedDomain.OutboxEventCategoryDeleted:
+		return domain.ReconcileReasonNotDeleted
+	case force:
+		return domain.ReconcileReasonForced
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package grpcservice
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// catalogRepository holds categories ordered by ID and records the outbox
+// events written. Methods the reconciler does not use panic through the nil
+// embedded interface.
+type catalogRepository struct {
+	repository.CategoryRepository
+
+	categories []*sharedDomain.Category
+	// current replaces a category when it is locked, as if it changed
+	// after it was read.
+	current map[uint]*sharedDomain.Category
+
+	afterIDs []uint
+	events   []*sharedDomain.OutboxEvent
+}
+
+func (r *catalogRepository) WithTransaction(ctx context.Context, fn func(repo repository.CategoryRepository) error) error {
+	return fn(r)
+}
+
+func (r *catalogRepository) GetCategoriesAfterID(ctx context.Context, afterID uint, limit int) ([]*sharedDomain.Category, error) {
+	r.afterIDs = append(r.afterIDs, afterID)
+	var page []*sharedDomain.Category
+	for _, category := range r.categories {
+		if category.ID > afterID && len(page) < limit {
+			page = append(page, category)
+		}
+	}
+	return page, nil
+}
+
+func (r *catalogRepository) GetCategoryForUpdate(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	if category, ok := r.current[id]; ok {
+		if category == nil {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return category, nil
+	}
+	for _, category := range r.categories {
+		if category.ID == id {
+			return category, nil
+		}
+	}
+	return nil, domain.ErrCategoryNotFound
+}
+
+func (r *catalogRepository) SaveOutboxEvent(ctx context.Context, event *sharedDomain.OutboxEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func liveCategory(id uint, name string) *sharedDomain.Category {
+	return &sharedDomain.Category{ID: id, Name: name, Slug: name}
+}
+
+func deletedCategory(id uint) *sharedDomain.Category {
+	return &sharedDomain.Category{ID: id, Name: "deleted", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+}
+
+func TestCategoryReconcilerReconcile(t *testing.T) {
+	sent := func(category *sharedDomain.Category) *sharedDomain.OutboxEvent {
+		event, err := sharedDomain.NewCategorySavedEvent(category, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+	deleted := func(id uint) *sharedDomain.OutboxEvent {
+		event, err := sharedDomain.NewCategoryDeletedEvent(id, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	// 1 is in sync, 2 was never sent, 3 is outdated, 4 is deleted but
+	// not sent, 5 is deleted and sent.
+	categories := []*sharedDomain.Category{liveCategory(1, "a"), liveCategory(2, "b"), liveCategory(3, "c"), deletedCategory(4), deletedCategory(5)}
+	lastSent := map[uint]*sharedDomain.OutboxEvent{
+		1: sent(liveCategory(1, "a")),
+		3: sent(liveCategory(3, "old")),
+		4: sent(liveCategory(4, "deleted")),
+		5: deleted(5),
+	}
+
+	tests := []struct {
+		name         string
+		opts         ReconcileOptions
+		current      map[uint]*sharedDomain.Category
+		wantAfterIDs []uint
+		wantEvents   []string
+		wantPushed   int
+		wantDeleted  int
+	}{
+		{
+			name:         "differences are queued",
+			opts:         ReconcileOptions{BatchSize: 2},
+			wantAfterIDs: []uint{0, 2, 4},
+			wantEvents:   []string{"category.saved 2 b", "category.saved 3 c", "category.deleted 4 "},
+			wantPushed:   2,
+			wantDeleted:  1,
+		},
+		{
+			name:         "dry run queues nothing",
+			opts:         ReconcileOptions{BatchSize: 10, DryRun: true},
+			wantAfterIDs: []uint{0},
+			wantPushed:   2,
+			wantDeleted:  1,
+		},
+		{
+			name:         "force queues everything",
+			opts:         ReconcileOptions{BatchSize: 10, Force: true},
+			wantAfterIDs: []uint{0},
+			wantEvents:   []string{"category.saved 1 a", "category.saved 2 b", "category.saved 3 c", "category.deleted 4 ", "category.deleted 5 "},
+			wantPushed:   3,
+			wantDeleted:  2,
+		},
+		{
+			// The queued event carries the state at the time it is
+			// written, not the one read for the comparison.
+			name:         "changes after reading are queued as they are",
+			opts:         ReconcileOptions{BatchSize: 10},
+			current:      map[uint]*sharedDomain.Category{2: liveCategory(2, "renamed"), 3: deletedCategory(3), 4: nil},
+			wantAfterIDs: []uint{0},
+			wantEvents:   []string{"category.saved 2 renamed", "category.deleted 3 "},
+			wantPushed:   2,
+			wantDeleted:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &catalogRepository{categories: categories, current: tt.current}
+			reconciler := NewCategoryReconciler(repo, &fakeOutboxRepository{lastSent: lastSent}, &recordingLogger{})
+
+			report, err := reconciler.Reconcile(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(repo.afterIDs, tt.wantAfterIDs) {
+				t.Errorf("read pages after IDs %v, want %v", repo.afterIDs, tt.wantAfterIDs)
+			}
+			var events []string
+			for _, event := range repo.events {
+				var payload struct{ Name string }
+				_ = json.Unmarshal([]byte(event.Payload), &payload)
+				events = append(events, fmt.Sprintf("%s %d %s", event.EventType, event.AggregateID, payload.Name))
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("queued %v, want %v", events, tt.wantEvents)
+			}
+			if report.Checked != len(categories) || report.Pushed != tt.wantPushed || report.Deleted != tt.wantDeleted {
+				t.Errorf("report checked %d, pushed %d, deleted %d; want %d, %d, %d",
+					report.Checked, report.Pushed, report.Deleted, len(categories), tt.wantPushed, tt.wantDeleted)
+			}
+		})
+	}
+}
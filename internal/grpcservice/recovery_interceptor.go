@@ -0,0 +1,29 @@
+package grpcservice
+
+import (
+	"category-service/pkg/logger"
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryInterceptor turns a panic in a handler into an Internal
+// error, like gin.Recovery does for the HTTP API, and logs it with the stack.
+// It should follow RequestLoggingUnaryInterceptor so the panic is logged with
+// the request ID.
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(ctx).Error(fmt.Sprintf("Panic in %s: %v\n%s", info.FullMethod, r, debug.Stack()), "grpc_panic", info.FullMethod)
+				res, err = nil, status.Error(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
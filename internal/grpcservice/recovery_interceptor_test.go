@@ -0,0 +1,60 @@
+package grpcservice
+
+import (
+	"category-service/pkg/logger"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/category.CategoryService/GetCategory"}
+
+	tests := []struct {
+		name       string
+		handler    grpc.UnaryHandler
+		wantRes    interface{}
+		wantCode   codes.Code
+		wantErrors int
+	}{
+		{
+			name:    "result is passed through",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil },
+			wantRes: "ok",
+		},
+		{
+			name: "error is passed through",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:       "panic becomes an internal error",
+			handler:    func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") },
+			wantCode:   codes.Internal,
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &recordingLogger{}
+			ctx := logger.WithContext(context.Background(), log)
+
+			res, err := RecoveryUnaryInterceptor()(ctx, nil, info, tt.handler)
+			if res != tt.wantRes {
+				t.Errorf("response = %v, want %v", res, tt.wantRes)
+			}
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("error = %v, want code %s", err, tt.wantCode)
+			}
+			if log.errors != tt.wantErrors {
+				t.Errorf("logged %d errors, want %d", log.errors, tt.wantErrors)
+			}
+		})
+	}
+}
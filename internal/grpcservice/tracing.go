@@ -0,0 +1,102 @@
+package grpcservice
+
+import (
+	"category-service/pkg/logger"
+	"category-service/pkg/tracing"
+	"context"
+	"path"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer("category-service/internal/grpcservice")
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry propagators.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TracingUnaryInterceptor starts a server span for every call, continuing the
+// trace of incoming traceparent metadata. It must be chained after
+// RequestLoggingUnaryInterceptor, so the trace ID is added to its logger.
+func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+		ctx, span := tracer.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(rpcAttributes(info.FullMethod)...),
+		)
+		defer span.End()
+
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			ctx = addLogFields(ctx, logger.Fields{"trace_id": traceID})
+		}
+
+		res, err := handler(ctx, req)
+		recordRPCStatus(span, err)
+		return res, err
+	}
+}
+
+// bookClientTracingInterceptor records calls to the Book service as client
+// spans and passes the trace context on in the call metadata.
+func bookClientTracingInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(rpcAttributes(method)...),
+	)
+	defer span.End()
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	recordRPCStatus(span, err)
+	return err
+}
+
+// rpcAttributes describes fullMethod, e.g. "/book.BookService/ReceiveCategory".
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.RPCSystemGRPC,
+		semconv.RPCService(path.Base(path.Dir(fullMethod))),
+		semconv.RPCMethod(path.Base(fullMethod)),
+	}
+}
+
+func recordRPCStatus(span trace.Span, err error) {
+	st := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(st.Code())))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, st.Message())
+	}
+}
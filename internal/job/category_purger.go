@@ -0,0 +1,92 @@
+package job
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/usecase"
+	"category-service/pkg/logger"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type CategoryPurgerConfig struct {
+	// Retention is how long soft-deleted categories are kept. Zero disables
+	// the periodic purge; on-demand purges still work.
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DefaultCategoryPurgerConfig returns the settings used when none are configured.
+func DefaultCategoryPurgerConfig() CategoryPurgerConfig {
+	return CategoryPurgerConfig{
+		Retention: 30 * 24 * time.Hour,
+		Interval:  time.Hour,
+		BatchSize: 100,
+	}
+}
+
+// CategoryPurger permanently deletes soft-deleted categories once their
+// retention period has passed.
+type CategoryPurger struct {
+	usecase usecase.CategoryUsecase
+	logger  logger.Logger
+	cfg     CategoryPurgerConfig
+}
+
+func NewCategoryPurger(uc usecase.CategoryUsecase, logger logger.Logger, cfg CategoryPurgerConfig) *CategoryPurger {
+	return &CategoryPurger{usecase: uc, logger: logger, cfg: cfg}
+}
+
+// Run purges expired categories every interval until ctx is cancelled.
+func (p *CategoryPurger) Run(ctx context.Context) {
+	if p.cfg.Retention <= 0 {
+		p.logger.Info("Category retention is disabled, not purging deleted categories", "purge", "disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error(fmt.Sprintf("Failed to purge deleted categories: %v", err), "purge", "retention")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired permanently deletes categories whose retention period has
+// passed.
+func (p *CategoryPurger) PurgeExpired(ctx context.Context) (int, error) {
+	if p.cfg.Retention <= 0 {
+		return 0, domain.ErrRetentionDisabled
+	}
+	return p.PurgeOlderThan(ctx, p.cfg.Retention)
+}
+
+// PurgeOlderThan permanently deletes categories that have been in the trash
+// for longer than age and returns how many were removed.
+func (p *CategoryPurger) PurgeOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	purged, err := p.usecase.PurgeDeletedCategories(ctx, time.Now().Add(-age), p.cfg.BatchSize)
+	if purged > 0 || err == nil {
+		p.logger.Info(fmt.Sprintf("Purged %d deleted categories older than %s", purged, age), "purge", "retention")
+	}
+	return purged, err
+}
+
+// PurgeCategory permanently deletes one category from the trash.
+func (p *CategoryPurger) PurgeCategory(ctx context.Context, id uint) error {
+	if err := p.usecase.PurgeCategory(ctx, id); err != nil {
+		return err
+	}
+
+	p.logger.Info("Purged 1 deleted category", "purge", "id:"+strconv.FormatUint(uint64(id), 10))
+	return nil
+}
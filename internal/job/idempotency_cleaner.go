@@ -0,0 +1,43 @@
+package job
+
+import (
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKeyCleaner periodically deletes expired idempotency keys.
+// Expired keys are already ignored by the middleware; this only keeps the
+// table small.
+type IdempotencyKeyCleaner struct {
+	repo     repository.IdempotencyRepository
+	logger   logger.Logger
+	interval time.Duration
+}
+
+func NewIdempotencyKeyCleaner(repo repository.IdempotencyRepository, logger logger.Logger, interval time.Duration) *IdempotencyKeyCleaner {
+	return &IdempotencyKeyCleaner{repo: repo, logger: logger, interval: interval}
+}
+
+// Run deletes expired keys every interval until ctx is cancelled.
+func (j *IdempotencyKeyCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := j.repo.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			j.logger.Error(fmt.Sprintf("Failed to delete expired idempotency keys: %v", err), "idempotency", "cleanup")
+		} else if deleted > 0 {
+			j.logger.Info(fmt.Sprintf("Deleted %d expired idempotency keys", deleted), "idempotency", "cleanup")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
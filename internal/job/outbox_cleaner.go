@@ -0,0 +1,50 @@
+package job
+
+import (
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// OutboxCleaner periodically deletes outbox events that were delivered
+// longer than the retention ago. The last sent event of every category is
+// kept, since the reconciler compares against it.
+type OutboxCleaner struct {
+	repo      repository.OutboxRepository
+	logger    logger.Logger
+	interval  time.Duration
+	retention time.Duration
+}
+
+func NewOutboxCleaner(repo repository.OutboxRepository, logger logger.Logger, interval, retention time.Duration) *OutboxCleaner {
+	return &OutboxCleaner{repo: repo, logger: logger, interval: interval, retention: retention}
+}
+
+// Run deletes old sent events every interval until ctx is cancelled. A
+// retention of zero keeps them.
+func (j *OutboxCleaner) Run(ctx context.Context) {
+	if j.retention <= 0 {
+		j.logger.Info("Outbox retention is disabled, not deleting sent events", "outbox", "cleanup")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := j.repo.DeleteSentEvents(ctx, time.Now().Add(-j.retention))
+		if err != nil && ctx.Err() == nil {
+			j.logger.Error(fmt.Sprintf("Failed to delete sent outbox events: %v", err), "outbox", "cleanup")
+		} else if deleted > 0 {
+			j.logger.Info(fmt.Sprintf("Deleted %d sent outbox events", deleted), "outbox", "cleanup")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package repository
 
 import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
 	sharedDomain "category-service/pkg/shared/domain"
 	"context"
-	"log"
+	"fmt"
+	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var tracer = otel.Tracer("category-service/internal/repository")
+
 type categoryRepository struct {
 	db *gorm.DB
 }
@@ -16,10 +24,61 @@ func NewAuthorRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
-func (r *categoryRepository) GetAllCategories(ctx context.Context, page, limit int) ([]*sharedDomain.Category, int64, error) {
+func (r *categoryRepository) WithTransaction(ctx context.Context, fn func(repo CategoryRepository) error) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.WithTransaction")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&categoryRepository{db: tx})
+	})
+}
+
+// categorySortColumns whitelists the fields categories can be sorted by.
+var categorySortColumns = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *categoryRepository) SavePoint(ctx context.Context, name string) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SavePoint")
+	defer span.End()
+
+	return r.db.WithContext(ctx).SavePoint(name).Error
+}
+
+func (r *categoryRepository) RollbackTo(ctx context.Context, name string) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.RollbackTo")
+	defer span.End()
+
+	return r.db.WithContext(ctx).RollbackTo(name).Error
+}
+
+func (r *categoryRepository) LockHierarchy(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.LockHierarchy")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Exec(`SELECT pg_advisory_xact_lock(hashtext('categories.hierarchy'))`).Error
+}
+
+func (r *categoryRepository) LockSlugBackfill(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.LockSlugBackfill")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Exec(`SELECT pg_advisory_xact_lock(hashtext('categories.slug_backfill'))`).Error
+}
+
+func (r *categoryRepository) GetAllCategories(ctx context.Context, req *domain.PaginationRequest) ([]*sharedDomain.Category, int64, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetAllCategories")
+	defer span.End()
+
 	var categories []*sharedDomain.Category
 	var totalRows int64
 
+	page, limit := req.Page, req.Limit
 	if page < 1 {
 		page = 1
 	}
@@ -27,37 +86,660 @@ func (r *categoryRepository) GetAllCategories(ctx context.Context, page, limit i
 		limit = 10
 	}
 
-	if err := r.db.Model(&sharedDomain.Category{}).Count(&totalRows).Error; err != nil {
-		log.Println("GetAllCategories count error:", err)
+	query := r.filterCategories(ctx, req)
+	if err := query.Count(&totalRows).Error; err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetAllCategories count error: %v", err), "repository", "GetAllCategories")
 		return nil, 0, err
 	}
 
 	offset := (page - 1) * limit
 
-	err := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&categories).Error
+	err := query.Limit(limit).Offset(offset).Order(categoryOrder(req)).Find(&categories).Error
 	if err != nil {
-		log.Println("GetAllCategories query error:", err)
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetAllCategories query error: %v", err), "repository", "GetAllCategories")
 		return nil, 0, err
 	}
 
 	return categories, totalRows, nil
 }
 
-func (r *categoryRepository) GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+func (r *categoryRepository) GetCategoriesByCursor(ctx context.Context, req *domain.PaginationRequest, cursor *domain.CategoryCursor) ([]*sharedDomain.Category, bool, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoriesByCursor")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	// Walking backwards flips both the comparison and the order; the
+	// result is reversed again below so callers always get list order.
+	descending := req.Order != "asc"
+	if cursor != nil && cursor.Backward {
+		descending = !descending
+	}
+
+	query := r.filterCategories(ctx, req)
+	if cursor != nil {
+		operator := ">"
+		if descending {
+			operator = "<"
+		}
+		query = query.Where("(created_at, id) "+operator+" (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	err := query.Order("created_at " + direction + ", id " + direction).Limit(limit + 1).Find(&categories).Error
+	if err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetCategoriesByCursor query error: %v", err), "repository", "GetCategoriesByCursor")
+		return nil, false, err
+	}
+
+	hasMore := len(categories) > limit
+	if hasMore {
+		categories = categories[:limit]
+	}
+
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(categories)-1; i < j; i, j = i+1, j-1 {
+			categories[i], categories[j] = categories[j], categories[i]
+		}
+	}
+
+	return categories, hasMore, nil
+}
+
+func (r *categoryRepository) filterCategories(ctx context.Context, req *domain.PaginationRequest) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&sharedDomain.Category{})
+	if req.IncludeDeleted {
+		query = query.Unscoped()
+	}
+
+	if req.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+likeEscaper.Replace(req.Search)+"%")
+	}
+	if req.Prefix != "" {
+		query = query.Where("name ILIKE ?", likeEscaper.Replace(req.Prefix)+"%")
+	}
+	if req.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *req.CreatedFrom)
+	}
+	if req.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *req.CreatedTo)
+	}
+	if req.UpdatedFrom != nil {
+		query = query.Where("updated_at >= ?", *req.UpdatedFrom)
+	}
+	if req.UpdatedTo != nil {
+		query = query.Where("updated_at <= ?", *req.UpdatedTo)
+	}
+
+	return query
+}
+
+// categoryOrder builds the ORDER BY clause, defaulting to newest first. The id
+// is always appended as a tiebreaker so that pages are stable.
+func categoryOrder(req *domain.PaginationRequest) string {
+	column, ok := categorySortColumns[req.Sort]
+	if !ok {
+		column = "created_at"
+	}
+
+	direction := "DESC"
+	if req.Order == "asc" {
+		direction = "ASC"
+	}
+
+	if column == "id" {
+		return "id " + direction
+	}
+	return column + " " + direction + ", id " + direction
+}
+
+func (r *categoryRepository) StreamCategories(ctx context.Context, fn func(row *domain.CategoryExportRow) error) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.StreamCategories")
+	defer span.End()
+
+	// Categories the walk from the roots does not reach, because they are
+	// nested too deeply or their parent chain is broken, are counted on
+	// every row, so the export fails before the first row is written.
+	rows, err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM categories
+			WHERE parent_id IS NULL AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, t.depth + 1 FROM categories c
+			JOIN tree t ON c.parent_id = t.id
+			WHERE c.deleted_at IS NULL AND t.depth < ?
+		)
+		SELECT c.id, c.name, c.slug, c.parent_id, p.name AS parent_name, c.created_at, c.updated_at,
+			count(*) FILTER (WHERE t.id IS NULL) OVER () AS unreachable
+		FROM categories c
+		LEFT JOIN tree t ON t.id = c.id
+		LEFT JOIN categories p ON p.id = c.parent_id
+		WHERE c.deleted_at IS NULL
+		ORDER BY t.depth, c.id`, domain.MaxCategoryDepth).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			domain.CategoryExportRow
+			Unreachable int64
+		}
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if row.Unreachable > 0 {
+			return domain.NewConflictError("CATEGORY_TREE_TOO_DEEP", fmt.Sprintf(
+				"%d categories are nested deeper than %d levels or have a broken parent chain and cannot be exported",
+				row.Unreachable, domain.MaxCategoryDepth))
+		}
+		if err := fn(&row.CategoryExportRow); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *categoryRepository) SyncCategoryIDSequence(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SyncCategoryIDSequence")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Exec(`SELECT setval(pg_get_serial_sequence('categories', 'id'), GREATEST((SELECT MAX(id) FROM categories), 1))`).Error
+}
+
+func (r *categoryRepository) CountCategories(ctx context.Context) (live, deleted int64, err error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.CountCategories")
+	defer span.End()
+
+	var counts struct {
+		Live    int64
+		Deleted int64
+	}
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT count(*) FILTER (WHERE deleted_at IS NULL) AS live,
+			count(*) FILTER (WHERE deleted_at IS NOT NULL) AS deleted
+		FROM categories`).Scan(&counts).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return counts.Live, counts.Deleted, nil
+}
+
+func (r *categoryRepository) GetDeletedCategories(ctx context.Context, page, limit int) ([]*sharedDomain.Category, int64, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetDeletedCategories")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+	var totalRows int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	query := r.db.WithContext(ctx).Unscoped().Model(&sharedDomain.Category{}).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&totalRows).Error; err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetDeletedCategories count error: %v", err), "repository", "GetDeletedCategories")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+
+	err := query.Limit(limit).Offset(offset).Order("deleted_at DESC, id DESC").Find(&categories).Error
+	if err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetDeletedCategories query error: %v", err), "repository", "GetDeletedCategories")
+		return nil, 0, err
+	}
+
+	return categories, totalRows, nil
+}
+
+func (r *categoryRepository) GetCategoriesAfterID(ctx context.Context, afterID uint, limit int) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoriesAfterID")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Unscoped().Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) GetCategoryForUpdate(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoryForUpdate")
+	defer span.End()
+
+	var category sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotFound)
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) GetDeletedCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetDeletedCategoryByID")
+	defer span.End()
+
 	var category sharedDomain.Category
 
-	err := r.db.First(&category, id).Error
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&category).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotInTrash)
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) RestoreCategory(ctx context.Context, category *sharedDomain.Category) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.RestoreCategory")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Unscoped().Model(&sharedDomain.Category{}).
+		Where("id = ? AND deleted_at IS NOT NULL", category.ID).
+		Updates(map[string]interface{}{
+			"name":       category.Name,
+			"slug":       category.Slug,
+			"parent_id":  category.ParentID,
+			"updated_by": category.UpdatedBy,
+			"version":    gorm.Expr("version + 1"),
+			"deleted_at": nil,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return translateError(result.Error, domain.ErrCategoryNotInTrash)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryNotInTrash
+	}
+
+	category.DeletedAt = gorm.DeletedAt{}
+	category.Version++
+	return nil
+}
+
+func (r *categoryRepository) PurgeDeletedCategories(ctx context.Context, deletedBefore time.Time, limit int) ([]uint, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.PurgeDeletedCategories")
+	defer span.End()
+
+	var ids []uint
+
+	err := r.db.WithContext(ctx).Raw(`
+		DELETE FROM categories
+		WHERE id IN (
+			SELECT id FROM categories
+			WHERE deleted_at IS NOT NULL AND deleted_at < ?
+			ORDER BY deleted_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, deletedBefore, limit).Scan(&ids).Error
 	if err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("PurgeDeletedCategories error: %v", err), "repository", "PurgeDeletedCategories")
 		return nil, err
 	}
 
+	return ids, nil
+}
+
+func (r *categoryRepository) PurgeCategory(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.PurgeCategory")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&sharedDomain.Category{})
+	if result.Error != nil {
+		return translateError(result.Error, domain.ErrCategoryNotInTrash)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryNotInTrash
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoryByID")
+	defer span.End()
+
+	var category sharedDomain.Category
+
+	err := r.db.WithContext(ctx).First(&category, id).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotFound)
+	}
+
 	return &category, nil
 }
 
+func (r *categoryRepository) GetCategoryByName(ctx context.Context, name string) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoryByName")
+	defer span.End()
+
+	var category sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&category).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotFound)
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoryBySlug")
+	defer span.End()
+
+	var category sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotFound)
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategoriesWithoutSlug(ctx context.Context, limit int) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoriesWithoutSlug")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Unscoped().Where("slug IS NULL OR slug = ''").Order("id ASC").Limit(limit).Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) UpdateCategorySlug(ctx context.Context, id uint, slug string) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.UpdateCategorySlug")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Unscoped().Model(&sharedDomain.Category{}).Where("id = ?", id).UpdateColumn("slug", slug).Error
+	return translateError(err, domain.ErrCategoryNotFound)
+}
+
+func (r *categoryRepository) GetCategoriesByIDs(ctx context.Context, ids []uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoriesByIDs")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	if len(ids) == 0 {
+		return categories, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *sharedDomain.Category) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.CreateCategory")
+	defer span.End()
+
+	return translateError(r.db.WithContext(ctx).Create(category).Error, domain.ErrCategoryNotFound)
+}
+
 func (r *categoryRepository) SaveCategory(ctx context.Context, category *sharedDomain.Category) error {
-	return r.db.Save(category).Error
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SaveCategory")
+	defer span.End()
+
+	version := category.Version
+	category.Version++
+
+	result := r.db.WithContext(ctx).Model(category).Where("version = ?", version).Select("*").Omit("created_at").Updates(category)
+	if result.Error != nil {
+		category.Version = version
+		return translateError(result.Error, domain.ErrCategoryNotFound)
+	}
+	if result.RowsAffected == 0 {
+		category.Version = version
+		return domain.ErrCategoryVersionMismatch
+	}
+	return nil
+}
+
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id, version uint) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.DeleteCategory")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("id = ? AND version = ?", id, version).Delete(&sharedDomain.Category{})
+	if result.Error != nil {
+		return translateError(result.Error, domain.ErrCategoryNotFound)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryVersionMismatch
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetSlugOwners(ctx context.Context, base string) (map[string]uint, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetSlugOwners")
+	defer span.End()
+
+	type slugOwner struct {
+		Slug       string
+		CategoryID uint
+	}
+	var owners []slugOwner
+
+	pattern := likeEscaper.Replace(base) + "-%"
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT slug, id AS category_id FROM categories WHERE slug = ? OR slug LIKE ?
+		UNION ALL
+		SELECT slug, category_id FROM category_slug_redirects WHERE slug = ? OR slug LIKE ?`,
+		base, pattern, base, pattern).Scan(&owners).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint, len(owners))
+	for _, owner := range owners {
+		result[owner.Slug] = owner.CategoryID
+	}
+
+	return result, nil
+}
+
+func (r *categoryRepository) GetSlugRedirect(ctx context.Context, slug string) (*sharedDomain.CategorySlugRedirect, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetSlugRedirect")
+	defer span.End()
+
+	var redirect sharedDomain.CategorySlugRedirect
+
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&redirect).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCategoryNotFound)
+	}
+
+	return &redirect, nil
 }
 
-func (r *categoryRepository) DeleteCategory(ctx context.Context, id uint) error {
-	return r.db.Where("id = ? ", id).Delete(&sharedDomain.Category{}).Error
+func (r *categoryRepository) SaveSlugRedirect(ctx context.Context, redirect *sharedDomain.CategorySlugRedirect) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SaveSlugRedirect")
+	defer span.End()
+
+	return translateError(r.db.WithContext(ctx).Create(redirect).Error, domain.ErrCategoryNotFound)
+}
+
+func (r *categoryRepository) DeleteSlugRedirect(ctx context.Context, slug string) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.DeleteSlugRedirect")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Where("slug = ?", slug).Delete(&sharedDomain.CategorySlugRedirect{}).Error
+}
+
+func (r *categoryRepository) DeleteSlugRedirectsByCategoryIDs(ctx context.Context, ids []uint) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.DeleteSlugRedirectsByCategoryIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("category_id IN ?", ids).Delete(&sharedDomain.CategorySlugRedirect{}).Error
+}
+
+func (r *categoryRepository) GetChildren(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetChildren")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Where("parent_id = ?", id).Order("name ASC").Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.CountChildren")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&sharedDomain.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepository) GetAncestors(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetAncestors")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1 FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE c.deleted_at IS NULL AND a.depth < ?
+		)
+		SELECT c.* FROM categories c
+		JOIN ancestors a ON a.id = c.id
+		WHERE a.depth > 0
+		ORDER BY a.depth DESC`, id, domain.MaxCategoryDepth).Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) GetSubtree(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetSubtree")
+	defer span.End()
+
+	var categories []*sharedDomain.Category
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, s.depth + 1 FROM categories c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE c.deleted_at IS NULL AND s.depth < ?
+		)
+		SELECT c.* FROM categories c
+		JOIN subtree s ON s.id = c.id
+		ORDER BY s.depth, c.name`, id, domain.MaxCategoryDepth).Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) GetSubtreeHeight(ctx context.Context, id uint) (int, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetSubtreeHeight")
+	defer span.End()
+
+	var height int
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, s.depth + 1 FROM categories c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE c.deleted_at IS NULL AND s.depth < ?
+		)
+		SELECT COALESCE(MAX(depth), 0) FROM subtree`, id, domain.MaxCategoryDepth).Scan(&height).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+func (r *categoryRepository) SaveOutboxEvent(ctx context.Context, event *sharedDomain.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SaveOutboxEvent")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *categoryRepository) SaveCategoryHistory(ctx context.Context, entry *sharedDomain.CategoryHistory) error {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.SaveCategoryHistory")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *categoryRepository) GetCategoryHistory(ctx context.Context, categoryID uint, page, limit int) ([]*sharedDomain.CategoryHistory, int64, error) {
+	ctx, span := tracer.Start(ctx, "CategoryRepository.GetCategoryHistory")
+	defer span.End()
+
+	var entries []*sharedDomain.CategoryHistory
+	var totalRows int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	query := r.db.WithContext(ctx).Model(&sharedDomain.CategoryHistory{}).Where("category_id = ?", categoryID)
+	if err := query.Count(&totalRows).Error; err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetCategoryHistory count error: %v", err), "repository", "GetCategoryHistory")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+
+	err := query.Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&entries).Error
+	if err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("GetCategoryHistory query error: %v", err), "repository", "GetCategoryHistory")
+		return nil, 0, err
+	}
+
+	return entries, totalRows, nil
 }
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"category-service/internal/domain"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// translateError converts GORM and PostgreSQL errors into domain errors so
+// that callers never depend on the database driver. notFound is returned for
+// missing records.
+func translateError(err error, notFound *domain.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound.Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			conflict := domain.ErrConflict
+			switch {
+			case strings.HasSuffix(pgErr.ConstraintName, "_pkey"):
+				conflict = domain.ErrCategoryIDTaken
+			case strings.Contains(pgErr.ConstraintName, "slug"):
+				conflict = domain.ErrCategorySlugTaken
+			case strings.Contains(pgErr.ConstraintName, "name"):
+				conflict = domain.ErrCategoryNameTaken
+			}
+			return conflict.Wrap(err)
+		case pgForeignKeyViolation:
+			return domain.NewValidationError("INVALID_REFERENCE", "referenced record does not exist").Wrap(err)
+		}
+	}
+
+	return err
+}
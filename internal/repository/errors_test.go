@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"category-service/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	other := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		err      error
+		want     error
+		wantKind domain.ErrorKind
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "record not found", err: gorm.ErrRecordNotFound, want: domain.ErrCategoryNotFound, wantKind: domain.KindNotFound},
+		{name: "wrapped record not found", err: fmt.Errorf("query: %w", gorm.ErrRecordNotFound), want: domain.ErrCategoryNotFound, wantKind: domain.KindNotFound},
+		{
+			name:     "primary key violation",
+			err:      &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "categories_pkey"},
+			want:     domain.ErrCategoryIDTaken,
+			wantKind: domain.KindConflict,
+		},
+		{
+			name:     "slug violation",
+			err:      &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "idx_categories_slug"},
+			want:     domain.ErrCategorySlugTaken,
+			wantKind: domain.KindConflict,
+		},
+		{
+			name:     "name violation",
+			err:      &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "idx_categories_name_live"},
+			want:     domain.ErrCategoryNameTaken,
+			wantKind: domain.KindConflict,
+		},
+		{
+			name:     "other unique violation",
+			err:      &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "categories_external_ref_key"},
+			want:     domain.ErrConflict,
+			wantKind: domain.KindConflict,
+		},
+		{
+			name:     "wrapped unique violation",
+			err:      fmt.Errorf("insert: %w", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "categories_pkey"}),
+			want:     domain.ErrCategoryIDTaken,
+			wantKind: domain.KindConflict,
+		},
+		{
+			name:     "foreign key violation",
+			err:      &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "fk_categories_parent"},
+			wantKind: domain.KindValidation,
+		},
+		{name: "other postgres error", err: &pgconn.PgError{Code: "40001"}},
+		{name: "other error", err: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, domain.ErrCategoryNotFound)
+
+			if tt.err == nil {
+				if got != nil {
+					t.Fatalf("translateError(nil) = %v, want nil", got)
+				}
+				return
+			}
+			if tt.want != nil && !errors.Is(got, tt.want) {
+				t.Errorf("translateError() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translateError() = %v, does not wrap %v", got, tt.err)
+			}
+
+			domainErr, ok := domain.AsError(got)
+			if tt.wantKind == "" {
+				if ok {
+					t.Errorf("translateError() = %v, want the error unchanged", got)
+				}
+				return
+			}
+			if !ok || domainErr.Kind != tt.wantKind {
+				t.Errorf("translateError() = %v, want kind %s", got, tt.wantKind)
+			}
+		})
+	}
+}
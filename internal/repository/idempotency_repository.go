@@ -0,0 +1,69 @@
+package repository
+
+import (
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error) {
+	// An expired key is taken over as if it did not exist.
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"owner", "request_hash", "status_code", "response_body", "response_headers", "created_at", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at < ?", Vars: []interface{}{time.Now()}},
+		}},
+	}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *idempotencyRepository) GetIdempotencyKey(ctx context.Context, userID uint, key string) (*sharedDomain.IdempotencyKey, error) {
+	var record sharedDomain.IdempotencyKey
+
+	err := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *idempotencyRepository) CompleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&sharedDomain.IdempotencyKey{}).
+		Where("user_id = ? AND key = ? AND owner = ?", key.UserID, key.Key, key.Owner).
+		Updates(map[string]interface{}{
+			"status_code":      key.StatusCode,
+			"response_body":    key.ResponseBody,
+			"response_headers": key.ResponseHeaders,
+			"expires_at":       key.ExpiresAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND key = ? AND owner = ?", key.UserID, key.Key, key.Owner).Delete(&sharedDomain.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&sharedDomain.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lockedUntil time.Time) ([]*sharedDomain.OutboxEvent, error) {
+	var events []*sharedDomain.OutboxEvent
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET locked_until = ?
+		WHERE id IN (
+			SELECT e.id FROM outbox_events e
+			WHERE e.status = ? AND e.next_attempt_at <= ?
+				AND (e.locked_until IS NULL OR e.locked_until < ?)
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_events p
+					WHERE p.aggregate_type = e.aggregate_type
+						AND p.aggregate_id = e.aggregate_id
+						AND p.status = ?
+						AND p.id < e.id
+				)
+			ORDER BY e.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		lockedUntil, sharedDomain.OutboxStatusPending, now, now, sharedDomain.OutboxStatusPending, limit,
+	).Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (r *outboxRepository) MarkEventSent(ctx context.Context, id uint, lockedUntil time.Time) (bool, error) {
+	now := time.Now()
+	return r.updateLeased(ctx, id, lockedUntil, map[string]interface{}{
+		"status":       sharedDomain.OutboxStatusSent,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"locked_until": nil,
+		"last_error":   "",
+		"processed_at": now,
+	})
+}
+
+func (r *outboxRepository) MarkEventRetry(ctx context.Context, id uint, lockedUntil time.Time, attempts int, nextAttemptAt time.Time, lastError string) (bool, error) {
+	return r.updateLeased(ctx, id, lockedUntil, map[string]interface{}{
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    nil,
+		"last_error":      lastError,
+	})
+}
+
+// updateLeased updates a pending event only while the lease taken by
+// ClaimPendingEvents with lockedUntil is still held.
+func (r *outboxRepository) updateLeased(ctx context.Context, id uint, lockedUntil time.Time, updates map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&sharedDomain.OutboxEvent{}).
+		Where("id = ? AND status = ? AND locked_until = ? AND locked_until > ?", id, sharedDomain.OutboxStatusPending, lockedUntil, time.Now()).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *outboxRepository) DeleteSentEvents(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM outbox_events e
+		WHERE e.status = ? AND e.processed_at < ?
+			AND EXISTS (
+				SELECT 1 FROM outbox_events n
+				WHERE n.aggregate_type = e.aggregate_type
+					AND n.aggregate_id = e.aggregate_id
+					AND n.status = ?
+					AND n.id > e.id
+			)`,
+		sharedDomain.OutboxStatusSent, before, sharedDomain.OutboxStatusSent,
+	)
+	return result.RowsAffected, result.Error
+}
+
+func (r *outboxRepository) GetLastSentEvents(ctx context.Context, categoryIDs []uint) (map[uint]*sharedDomain.OutboxEvent, error) {
+	lastSent := make(map[uint]*sharedDomain.OutboxEvent, len(categoryIDs))
+	if len(categoryIDs) == 0 {
+		return lastSent, nil
+	}
+
+	var events []*sharedDomain.OutboxEvent
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (aggregate_id) * FROM outbox_events
+		WHERE aggregate_type = ? AND aggregate_id IN ? AND status = ?
+		ORDER BY aggregate_id, id DESC`,
+		sharedDomain.OutboxAggregateCategory, categoryIDs, sharedDomain.OutboxStatusSent,
+	).Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		lastSent[event.AggregateID] = event
+	}
+	return lastSent, nil
+}
+
+func (r *outboxRepository) CountPendingEvents(ctx context.Context) (int64, time.Time, error) {
+	var backlog struct {
+		Count  int64
+		Oldest *time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&sharedDomain.OutboxEvent{}).
+		Select("count(*) AS count, min(created_at) AS oldest").
+		Where("status = ?", sharedDomain.OutboxStatusPending).
+		Scan(&backlog).Error
+	if err != nil || backlog.Oldest == nil {
+		return backlog.Count, time.Time{}, err
+	}
+	return backlog.Count, *backlog.Oldest, nil
+}
@@ -1,13 +1,152 @@
 package repository
 
 import (
+	"category-service/internal/domain"
 	sharedDomain "category-service/pkg/shared/domain"
 	"context"
+	"time"
 )
 
 type CategoryRepository interface {
-	SaveCategory(ctx context.Context, category *sharedDomain.Category) error
-	GetAllCategories(ctx context.Context, page, limit int) ([]*sharedDomain.Category, int64, error)
+	// WithTransaction runs fn with a repository bound to a single database
+	// transaction. The transaction is rolled back if fn returns an error.
+	WithTransaction(ctx context.Context, fn func(repo CategoryRepository) error) error
+	// SavePoint and RollbackTo allow undoing part of a transaction. They
+	// must only be used on a repository passed to WithTransaction.
+	SavePoint(ctx context.Context, name string) error
+	RollbackTo(ctx context.Context, name string) error
+	// LockHierarchy serializes changes to the category tree until the
+	// transaction ends. It must only be used on a repository passed to
+	// WithTransaction.
+	LockHierarchy(ctx context.Context) error
+	// LockSlugBackfill serializes slug backfills of instances starting at
+	// the same time until the transaction ends. It must only be used on a
+	// repository passed to WithTransaction.
+	LockSlugBackfill(ctx context.Context) error
+
+	CreateCategory(ctx context.Context, category *sharedDomain.Category) error
+	GetAllCategories(ctx context.Context, req *domain.PaginationRequest) ([]*sharedDomain.Category, int64, error)
+	// GetCategoriesByCursor returns up to req.Limit categories after cursor
+	// (or the first page if cursor is nil) in the requested order, and whether
+	// more categories follow in the direction of travel.
+	GetCategoriesByCursor(ctx context.Context, req *domain.PaginationRequest, cursor *domain.CategoryCursor) ([]*sharedDomain.Category, bool, error)
+	// StreamCategories calls fn for every live category without loading them
+	// all into memory. Parents are always visited before their children. It
+	// fails before calling fn if a live category cannot be reached from a
+	// root within domain.MaxCategoryDepth levels.
+	StreamCategories(ctx context.Context, fn func(row *domain.CategoryExportRow) error) error
+	// SyncCategoryIDSequence moves the id sequence past the highest id, which
+	// is needed after inserting categories with explicit ids.
+	SyncCategoryIDSequence(ctx context.Context) error
+	GetDeletedCategories(ctx context.Context, page, limit int) ([]*sharedDomain.Category, int64, error)
+	// GetCategoriesAfterID returns up to limit live and soft-deleted
+	// categories with an ID above afterID, ordered by ID.
+	GetCategoriesAfterID(ctx context.Context, afterID uint, limit int) ([]*sharedDomain.Category, error)
+	// GetCategoryForUpdate returns a live or soft-deleted category and locks
+	// it until the transaction ends. It must only be used on a repository
+	// passed to WithTransaction.
+	GetCategoryForUpdate(ctx context.Context, id uint) (*sharedDomain.Category, error)
+	// CountCategories returns the number of live and of soft-deleted categories.
+	CountCategories(ctx context.Context) (live, deleted int64, err error)
+	GetDeletedCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error)
+	// RestoreCategory clears deleted_at and stores the name, slug and parent
+	// of category, which may have changed to resolve conflicts.
+	RestoreCategory(ctx context.Context, category *sharedDomain.Category) error
+	// PurgeDeletedCategories permanently deletes up to limit categories that
+	// were soft-deleted before deletedBefore and returns their IDs.
+	PurgeDeletedCategories(ctx context.Context, deletedBefore time.Time, limit int) ([]uint, error)
+	// PurgeCategory permanently deletes a soft-deleted category.
+	PurgeCategory(ctx context.Context, id uint) error
+	DeleteSlugRedirectsByCategoryIDs(ctx context.Context, ids []uint) error
 	GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error)
-	DeleteCategory(ctx context.Context, id uint) error
+	GetCategoryByName(ctx context.Context, name string) (*sharedDomain.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*sharedDomain.Category, error)
+	GetCategoriesWithoutSlug(ctx context.Context, limit int) ([]*sharedDomain.Category, error)
+	UpdateCategorySlug(ctx context.Context, id uint, slug string) error
+	GetCategoriesByIDs(ctx context.Context, ids []uint) ([]*sharedDomain.Category, error)
+	// SaveCategory updates category if it is still at category.Version and
+	// increments the version, failing with ErrCategoryVersionMismatch otherwise.
+	SaveCategory(ctx context.Context, category *sharedDomain.Category) error
+	// DeleteCategory soft-deletes a category if it is still at version.
+	DeleteCategory(ctx context.Context, id, version uint) error
+
+	// GetSlugOwners returns the categories that hold base or base-<n> either as
+	// their current slug or as a redirect, keyed by slug. Soft-deleted
+	// categories are included because they still occupy the unique index.
+	GetSlugOwners(ctx context.Context, base string) (map[string]uint, error)
+	GetSlugRedirect(ctx context.Context, slug string) (*sharedDomain.CategorySlugRedirect, error)
+	SaveSlugRedirect(ctx context.Context, redirect *sharedDomain.CategorySlugRedirect) error
+	DeleteSlugRedirect(ctx context.Context, slug string) error
+
+	GetChildren(ctx context.Context, id uint) ([]*sharedDomain.Category, error)
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	// GetAncestors returns the ancestors of a category ordered from the root
+	// down to its direct parent.
+	GetAncestors(ctx context.Context, id uint) ([]*sharedDomain.Category, error)
+	// GetSubtree returns a category together with all of its descendants.
+	GetSubtree(ctx context.Context, id uint) ([]*sharedDomain.Category, error)
+	// GetSubtreeHeight returns how many levels of descendants a category
+	// has, 0 for a leaf or a category that is not live. The walk stops at
+	// domain.MaxCategoryDepth levels.
+	GetSubtreeHeight(ctx context.Context, id uint) (int, error)
+
+	// SaveCategoryHistory appends an entry to the change log of a category.
+	// History entries are never updated or deleted.
+	SaveCategoryHistory(ctx context.Context, entry *sharedDomain.CategoryHistory) error
+	GetCategoryHistory(ctx context.Context, categoryID uint, page, limit int) ([]*sharedDomain.CategoryHistory, int64, error)
+
+	SaveOutboxEvent(ctx context.Context, event *sharedDomain.OutboxEvent) error
+}
+
+type OutboxRepository interface {
+	// ClaimPendingEvents leases up to limit due events until lockedUntil so
+	// that concurrent dispatchers do not deliver the same event twice. At most
+	// one event per aggregate is returned, always the oldest pending one, so
+	// events for a category are delivered in the order they were written.
+	ClaimPendingEvents(ctx context.Context, limit int, lockedUntil time.Time) ([]*sharedDomain.OutboxEvent, error)
+	// MarkEventSent and MarkEventRetry record the outcome of a delivery if
+	// the event is still leased until lockedUntil, and report whether it
+	// was. An expired lease may have been taken over by another dispatcher,
+	// whose outcome must not be overwritten.
+	MarkEventSent(ctx context.Context, id uint, lockedUntil time.Time) (bool, error)
+	MarkEventRetry(ctx context.Context, id uint, lockedUntil time.Time, attempts int, nextAttemptAt time.Time, lastError string) (bool, error)
+	// DeleteSentEvents deletes events delivered before the given time,
+	// except the last delivered event of each aggregate, which
+	// GetLastSentEvents reports.
+	DeleteSentEvents(ctx context.Context, before time.Time) (int64, error)
+	// GetLastSentEvents returns the most recently delivered event of each of
+	// the given categories, keyed by category ID. Categories without a
+	// delivered event are missing from the map.
+	GetLastSentEvents(ctx context.Context, categoryIDs []uint) (map[uint]*sharedDomain.OutboxEvent, error)
+	// CountPendingEvents returns the number of undelivered events and the
+	// creation time of the oldest one, zero if there is none.
+	CountPendingEvents(ctx context.Context) (count int64, oldest time.Time, err error)
+}
+
+type IdempotencyRepository interface {
+	// ReserveIdempotencyKey stores key as in progress and reports whether it
+	// was reserved; false means an unexpired record for the key exists.
+	ReserveIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error)
+	// GetIdempotencyKey returns nil if no record exists for the key.
+	GetIdempotencyKey(ctx context.Context, userID uint, key string) (*sharedDomain.IdempotencyKey, error)
+	// CompleteIdempotencyKey stores the response of key and reports whether
+	// key.Owner still held it. DeleteIdempotencyKey releases key if
+	// key.Owner still holds it. A key whose lease expired may have been
+	// taken over by a retry, whose record must not be overwritten.
+	CompleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error)
+	DeleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+type RevocationRepository interface {
+	SaveRevokedToken(ctx context.Context, token *sharedDomain.RevokedToken) error
+	SaveUserTokenRevocation(ctx context.Context, revocation *sharedDomain.UserTokenRevocation) error
+	// GetRevokedTokens returns the revoked tokens that have not expired yet.
+	GetRevokedTokens(ctx context.Context) ([]*sharedDomain.RevokedToken, error)
+	GetUserTokenRevocations(ctx context.Context) ([]*sharedDomain.UserTokenRevocation, error)
+	// DeleteRevokedToken and DeleteUserTokenRevocation report whether an
+	// entry existed.
+	DeleteRevokedToken(ctx context.Context, jti string) (bool, error)
+	DeleteUserTokenRevocation(ctx context.Context, userID uint) (bool, error)
+	DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error)
 }
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepository(db *gorm.DB) RevocationRepository {
+	return &revocationRepository{db: db}
+}
+
+func (r *revocationRepository) SaveRevokedToken(ctx context.Context, token *sharedDomain.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "jti"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "expires_at"}),
+	}).Create(token).Error
+}
+
+func (r *revocationRepository) SaveUserTokenRevocation(ctx context.Context, revocation *sharedDomain.UserTokenRevocation) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "reason", "updated_at"}),
+	}).Create(revocation).Error
+}
+
+func (r *revocationRepository) GetRevokedTokens(ctx context.Context) ([]*sharedDomain.RevokedToken, error) {
+	var tokens []*sharedDomain.RevokedToken
+
+	err := r.db.WithContext(ctx).Where("expires_at IS NULL OR expires_at > ?", time.Now()).Order("revoked_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *revocationRepository) GetUserTokenRevocations(ctx context.Context) ([]*sharedDomain.UserTokenRevocation, error) {
+	var revocations []*sharedDomain.UserTokenRevocation
+
+	err := r.db.WithContext(ctx).Order("updated_at DESC").Find(&revocations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return revocations, nil
+}
+
+func (r *revocationRepository) DeleteRevokedToken(ctx context.Context, jti string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("jti = ?", jti).Delete(&sharedDomain.RevokedToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *revocationRepository) DeleteUserTokenRevocation(ctx context.Context, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&sharedDomain.UserTokenRevocation{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *revocationRepository) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&sharedDomain.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errBatchAborted rolls back an atomic batch after an operation failed; the
+// failure itself is reported in the batch results.
+var errBatchAborted = errors.New("batch aborted")
+
+func (uc *categoryUsecase) BatchCategories(ctx context.Context, req *domain.BatchCategoryRequest) (*domain.BatchCategoryResponse, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.BatchCategories")
+	defer span.End()
+
+	mode := req.Mode
+	if mode == "" {
+		mode = domain.BatchModeAtomic
+	}
+
+	var res *domain.BatchCategoryResponse
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		res = &domain.BatchCategoryResponse{Mode: mode, Results: make([]*domain.BatchCategoryResult, 0, len(req.Operations))}
+
+		for i := range req.Operations {
+			op := &req.Operations[i]
+			result := &domain.BatchCategoryResult{Index: i, Op: op.Op}
+			res.Results = append(res.Results, result)
+
+			savePoint := fmt.Sprintf("batch_op_%d", i)
+			if mode == domain.BatchModePartial {
+				if err := repo.SavePoint(ctx, savePoint); err != nil {
+					return err
+				}
+			}
+
+			category, err := uc.applyBatchOperation(ctx, repo, op)
+			if err == nil {
+				result.Status = domain.BatchStatusSucceeded
+				result.Category = category
+				res.Succeeded++
+				continue
+			}
+
+			// Only domain errors are attributed to the item; anything else
+			// (lost connection, broken transaction) fails the whole batch.
+			domainErr, ok := domain.AsError(err)
+			if !ok {
+				return err
+			}
+			result.Status = domain.BatchStatusFailed
+			result.Error = &domain.ItemError{Code: domainErr.Code, Message: domainErr.Message}
+			res.Failed++
+
+			if mode == domain.BatchModeAtomic {
+				return errBatchAborted
+			}
+			if err := repo.RollbackTo(ctx, savePoint); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errBatchAborted) {
+		for i := len(res.Results); i < len(req.Operations); i++ {
+			res.Results = append(res.Results, &domain.BatchCategoryResult{Index: i, Op: req.Operations[i].Op, Status: domain.BatchStatusSkipped})
+		}
+		// Earlier operations were rolled back along with the failed one.
+		for _, result := range res.Results {
+			if result.Status == domain.BatchStatusSucceeded {
+				result.Status = domain.BatchStatusSkipped
+				result.Category = nil
+			}
+		}
+		res.Succeeded = 0
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Committed = true
+	logger.FromContext(ctx).Info(fmt.Sprintf("Applied batch of %d operations (%d succeeded, %d failed)", len(req.Operations), res.Succeeded, res.Failed), "category", "batch")
+	return res, nil
+}
+
+func (uc *categoryUsecase) applyBatchOperation(ctx context.Context, repo repository.CategoryRepository, op *domain.BatchCategoryOperation) (*sharedDomain.Category, error) {
+	switch op.Op {
+	case domain.BatchOpCreate:
+		if op.Name == nil || *op.Name == "" {
+			return nil, domain.NewValidationError("NAME_REQUIRED", "name is required")
+		}
+		createReq := &domain.CreateCategoryRequest{Name: *op.Name, ParentID: op.ParentID.Value}
+		return uc.createCategory(ctx, repo, createReq)
+	case domain.BatchOpUpdate:
+		if op.ID == 0 {
+			return nil, domain.NewValidationError("ID_REQUIRED", "id is required")
+		}
+		updateReq := &domain.UpdateCategoryRequest{ID: op.ID, Name: op.Name, ParentID: op.ParentID}
+		return uc.updateCategory(ctx, repo, updateReq)
+	case domain.BatchOpDelete:
+		if op.ID == 0 {
+			return nil, domain.NewValidationError("ID_REQUIRED", "id is required")
+		}
+		return nil, uc.deleteCategory(ctx, repo, op.ID, nil)
+	default:
+		return nil, domain.NewValidationError("INVALID_OPERATION", fmt.Sprintf("unknown operation %q", op.Op))
+	}
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+)
+
+// GetCategoryHistory returns the change log of a category, newest first. The
+// history of deleted and purged categories stays available.
+func (uc *categoryUsecase) GetCategoryHistory(ctx context.Context, req *domain.CategoryHistoryRequest) (*domain.PaginatedResponse, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryHistory")
+	defer span.End()
+
+	entries, totalRows, err := uc.repo.GetCategoryHistory(ctx, req.ID, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalRows == 0 {
+		if err := uc.ensureCategoryKnown(ctx, req.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	paginatedResponse := &domain.PaginatedResponse{
+		Data:       entries,
+		Total:      totalRows,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int((totalRows + int64(req.Limit) - 1) / int64(req.Limit)),
+	}
+
+	return paginatedResponse, nil
+}
+
+// ensureCategoryKnown fails with ErrCategoryNotFound unless id is a live or
+// soft-deleted category.
+func (uc *categoryUsecase) ensureCategoryKnown(ctx context.Context, id uint) error {
+	_, err := uc.repo.GetCategoryByID(ctx, id)
+	if !errors.Is(err, domain.ErrCategoryNotFound) {
+		return err
+	}
+
+	if _, err := uc.repo.GetDeletedCategoryByID(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrCategoryNotInTrash) {
+			return domain.ErrCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// recordHistory appends a change of a category to its history, attributed to
+// the actor in ctx. before or after is nil when the operation has no such
+// state.
+func (uc *categoryUsecase) recordHistory(ctx context.Context, repo repository.CategoryRepository, categoryID uint, operation string, before, after *sharedDomain.CategorySnapshot) error {
+	return repo.SaveCategoryHistory(ctx, &sharedDomain.CategoryHistory{
+		CategoryID: categoryID,
+		Operation:  operation,
+		ActorID:    domain.ActorFromContext(ctx),
+		Before:     before,
+		After:      after,
+	})
+}
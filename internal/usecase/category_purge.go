@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeDeletedCategories permanently deletes categories soft-deleted before
+// deletedBefore, batchSize rows per transaction, and returns how many were
+// removed. The Book service was already told about the deletes, so no outbox
+// events are written.
+func (uc *categoryUsecase) PurgeDeletedCategories(ctx context.Context, deletedBefore time.Time, batchSize int) (int, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.PurgeDeletedCategories")
+	defer span.End()
+
+	total := 0
+	for ctx.Err() == nil {
+		var purged int
+		err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+			ids, err := repo.PurgeDeletedCategories(ctx, deletedBefore, batchSize)
+			if err != nil {
+				return err
+			}
+			purged = len(ids)
+			for _, id := range ids {
+				if err := uc.recordHistory(ctx, repo, id, sharedDomain.CategoryOperationPurge, nil, nil); err != nil {
+					return err
+				}
+			}
+			return repo.DeleteSlugRedirectsByCategoryIDs(ctx, ids)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += purged
+		if purged < batchSize {
+			return total, nil
+		}
+	}
+
+	return total, ctx.Err()
+}
+
+// PurgeCategory permanently deletes a category from the trash.
+func (uc *categoryUsecase) PurgeCategory(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.PurgeCategory")
+	defer span.End()
+
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		category, err := repo.GetDeletedCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.PurgeCategory(ctx, id); err != nil {
+			return err
+		}
+		if err := uc.recordHistory(ctx, repo, id, sharedDomain.CategoryOperationPurge, sharedDomain.NewCategorySnapshot(category), nil); err != nil {
+			return err
+		}
+		return repo.DeleteSlugRedirectsByCategoryIDs(ctx, []uint{id})
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info(fmt.Sprintf("Purged category %d", id), "category", sharedDomain.CategoryOperationPurge)
+	return nil
+}
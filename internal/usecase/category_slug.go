@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/slug"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const slugBackfillBatchSize = 100
+
+// GetCategoryBySlug looks a category up by its current slug or, failing that,
+// by one of its former slugs. redirected reports whether a former slug matched.
+func (uc *categoryUsecase) GetCategoryBySlug(ctx context.Context, value string) (*sharedDomain.Category, bool, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryBySlug")
+	defer span.End()
+
+	category, err := uc.repo.GetCategoryBySlug(ctx, value)
+	if err == nil {
+		return category, false, nil
+	}
+	if !errors.Is(err, domain.ErrCategoryNotFound) {
+		return nil, false, err
+	}
+
+	redirect, err := uc.repo.GetSlugRedirect(ctx, value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	category, err = uc.repo.GetCategoryByID(ctx, redirect.CategoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return category, true, nil
+}
+
+// BackfillSlugs assigns slugs to categories created before slugs existed and
+// notifies the Book service about the live ones.
+func (uc *categoryUsecase) BackfillSlugs(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.BackfillSlugs")
+	defer span.End()
+
+	total := 0
+	for {
+		backfilled := 0
+		err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+			// Instances starting together wait for each other here and
+			// then only see the categories still left without a slug.
+			if err := repo.LockSlugBackfill(ctx); err != nil {
+				return err
+			}
+			categories, err := repo.GetCategoriesWithoutSlug(ctx, slugBackfillBatchSize)
+			if err != nil {
+				return err
+			}
+			backfilled = len(categories)
+
+			for _, category := range categories {
+				value, err := uc.uniqueSlug(ctx, repo, category.Name, category.ID)
+				if err != nil {
+					return err
+				}
+				category.Slug = value
+
+				if err := repo.UpdateCategorySlug(ctx, category.ID, value); err != nil {
+					return err
+				}
+				if !category.DeletedAt.Valid {
+					if err := uc.enqueueCategorySaved(ctx, repo, category); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		if backfilled == 0 {
+			return total, nil
+		}
+		total += backfilled
+	}
+}
+
+// assignSlug derives the slug of category from its name. When the slug
+// changes, the previous one is kept as a redirect; a former slug of the same
+// category is reclaimed instead of getting a numeric suffix.
+func (uc *categoryUsecase) assignSlug(ctx context.Context, repo repository.CategoryRepository, category *sharedDomain.Category) error {
+	value, err := uc.uniqueSlug(ctx, repo, category.Name, category.ID)
+	if err != nil {
+		return err
+	}
+	if value == category.Slug {
+		return nil
+	}
+
+	if category.ID != 0 {
+		if err := repo.DeleteSlugRedirect(ctx, value); err != nil {
+			return err
+		}
+	}
+
+	if category.Slug != "" {
+		err := repo.SaveSlugRedirect(ctx, &sharedDomain.CategorySlugRedirect{Slug: category.Slug, CategoryID: category.ID})
+		if err != nil {
+			return err
+		}
+	}
+
+	category.Slug = value
+	return nil
+}
+
+// uniqueSlug returns the slug for name, suffixed with -2, -3, ... if another
+// category already holds it. Slugs held by categoryID itself are reusable.
+func (uc *categoryUsecase) uniqueSlug(ctx context.Context, repo repository.CategoryRepository, name string, categoryID uint) (string, error) {
+	base := slug.Make(name)
+
+	owners, err := repo.GetSlugOwners(ctx, base)
+	if err != nil {
+		return "", err
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		owner, taken := owners[candidate]
+		if !taken || (categoryID != 0 && owner == categoryID) {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"io"
+)
+
+const maxCategoryNameLength = 255
+
+// errImportDryRun rolls back the import transaction of a dry run after every
+// row was applied, so the report reflects what a real import would do.
+var errImportDryRun = errors.New("import dry run")
+
+func (uc *categoryUsecase) ExportCategories(ctx context.Context, fn func(row *domain.CategoryExportRow) error) error {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.ExportCategories")
+	defer span.End()
+
+	return uc.repo.StreamCategories(ctx, fn)
+}
+
+// ImportCategories upserts every row of source in one transaction. Rows that
+// fail are reported with their line number and skipped without affecting the
+// others. Categories are matched by name or, with match "id", by id, in
+// which case new categories keep the id given in the file.
+func (uc *categoryUsecase) ImportCategories(ctx context.Context, source domain.CategoryImportSource, req *domain.ImportCategoriesRequest) (*domain.ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.ImportCategories")
+	defer span.End()
+
+	match := req.Match
+	if match == "" {
+		match = domain.ImportMatchName
+	}
+
+	var report *domain.ImportReport
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		report = &domain.ImportReport{DryRun: req.DryRun, Match: match, Errors: []*domain.ImportLineError{}}
+		insertedIDs := false
+
+		for {
+			row, line, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			report.Total++
+
+			if err == nil {
+				var status string
+				status, err = uc.importRowInSavePoint(ctx, repo, row, match)
+				switch status {
+				case domain.ImportStatusCreated:
+					report.Created++
+					insertedIDs = insertedIDs || match == domain.ImportMatchID
+				case domain.ImportStatusUpdated:
+					report.Updated++
+				case domain.ImportStatusUnchanged:
+					report.Unchanged++
+				}
+				if err == nil {
+					continue
+				}
+			}
+
+			domainErr, ok := domain.AsError(err)
+			if !ok {
+				return err
+			}
+			report.Failed++
+			report.Errors = append(report.Errors, &domain.ImportLineError{
+				Line:      line,
+				ItemError: domain.ItemError{Code: domainErr.Code, Message: domainErr.Message},
+			})
+		}
+
+		if insertedIDs && !req.DryRun {
+			if err := repo.SyncCategoryIDSequence(ctx); err != nil {
+				return err
+			}
+		}
+
+		if req.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// importRowInSavePoint undoes the partial writes of a row that failed with a
+// domain error, so the transaction can continue with the next row.
+func (uc *categoryUsecase) importRowInSavePoint(ctx context.Context, repo repository.CategoryRepository, row *domain.CategoryImportRow, match string) (string, error) {
+	if err := repo.SavePoint(ctx, "import_row"); err != nil {
+		return "", err
+	}
+
+	status, err := uc.importRow(ctx, repo, row, match)
+	if _, ok := domain.AsError(err); ok {
+		if err := repo.RollbackTo(ctx, "import_row"); err != nil {
+			return "", err
+		}
+	}
+
+	return status, err
+}
+
+func (uc *categoryUsecase) importRow(ctx context.Context, repo repository.CategoryRepository, row *domain.CategoryImportRow, match string) (string, error) {
+	if row.Name == "" {
+		return "", domain.NewValidationError("NAME_REQUIRED", "name is required")
+	}
+	if len(row.Name) > maxCategoryNameLength {
+		return "", domain.NewValidationError("NAME_TOO_LONG", "name must not exceed 255 characters")
+	}
+	if match == domain.ImportMatchID && row.ID == 0 {
+		return "", domain.NewValidationError("ID_REQUIRED", "id is required when matching by id")
+	}
+
+	parentID := row.ParentID
+	if row.ParentName != nil && *row.ParentName != "" {
+		parent, err := repo.GetCategoryByName(ctx, *row.ParentName)
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return "", domain.ErrParentNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		parentID = &parent.ID
+	}
+
+	var existing *sharedDomain.Category
+	var err error
+	if match == domain.ImportMatchID {
+		existing, err = repo.GetCategoryByID(ctx, row.ID)
+	} else {
+		existing, err = repo.GetCategoryByName(ctx, row.Name)
+	}
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		createReq := &domain.CreateCategoryRequest{Name: row.Name, ParentID: parentID}
+		if match == domain.ImportMatchID {
+			createReq.ID = row.ID
+		}
+		if _, err := uc.createCategory(ctx, repo, createReq); err != nil {
+			return "", err
+		}
+		return domain.ImportStatusCreated, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if existing.Name == row.Name && equalParent(existing.ParentID, parentID) {
+		return domain.ImportStatusUnchanged, nil
+	}
+
+	updateReq := &domain.UpdateCategoryRequest{
+		ID:       existing.ID,
+		Name:     &row.Name,
+		ParentID: domain.OptionalUint{Set: true, Value: parentID},
+	}
+	if _, err := uc.updateCategory(ctx, repo, updateReq); err != nil {
+		return "", err
+	}
+	return domain.ImportStatusUpdated, nil
+}
+
+func equalParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
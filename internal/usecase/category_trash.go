@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxRestoreRenameAttempts bounds the search for a free name when a restore
+// is asked to rename automatically.
+const maxRestoreRenameAttempts = 100
+
+func (uc *categoryUsecase) GetTrash(ctx context.Context, req *domain.TrashRequest) (*domain.PaginatedResponse, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetTrash")
+	defer span.End()
+
+	categories, totalRows, err := uc.repo.GetDeletedCategories(ctx, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	paginatedResponse := &domain.PaginatedResponse{
+		Data:       categories,
+		Total:      totalRows,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int((totalRows + int64(req.Limit) - 1) / int64(req.Limit)),
+	}
+
+	return paginatedResponse, nil
+}
+
+// RestoreCategory undeletes a category and notifies the Book service about it
+// again. A parent that is no longer live is dropped, making the category a
+// root.
+func (uc *categoryUsecase) RestoreCategory(ctx context.Context, req *domain.RestoreCategoryRequest) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.RestoreCategory")
+	defer span.End()
+
+	var category *sharedDomain.Category
+
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		// The parent must stay live until the category is restored.
+		if err := repo.LockHierarchy(ctx); err != nil {
+			return err
+		}
+
+		deletedCategory, err := repo.GetDeletedCategoryByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		before := sharedDomain.NewCategorySnapshot(deletedCategory)
+
+		name := deletedCategory.Name
+		if req.Name != nil {
+			name = *req.Name
+		}
+
+		name, err = uc.restoreName(ctx, repo, name, req.AutoRename)
+		if err != nil {
+			return err
+		}
+		if name != deletedCategory.Name {
+			deletedCategory.Name = name
+			if err := uc.assignSlug(ctx, repo, deletedCategory); err != nil {
+				return err
+			}
+		}
+
+		if deletedCategory.ParentID != nil {
+			if err := uc.ensureValidParent(ctx, repo, deletedCategory.ID, *deletedCategory.ParentID); err != nil {
+				if !errors.Is(err, domain.ErrParentNotFound) {
+					return err
+				}
+				deletedCategory.ParentID = nil
+			}
+		}
+
+		deletedCategory.UpdatedBy = domain.ActorFromContext(ctx)
+		if err := repo.RestoreCategory(ctx, deletedCategory); err != nil {
+			return err
+		}
+		if err := uc.recordHistory(ctx, repo, deletedCategory.ID, sharedDomain.CategoryOperationRestore, before, sharedDomain.NewCategorySnapshot(deletedCategory)); err != nil {
+			return err
+		}
+		if err := uc.enqueueCategorySaved(ctx, repo, deletedCategory); err != nil {
+			return err
+		}
+
+		category = deletedCategory
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info(fmt.Sprintf("Restored category %d as %q", category.ID, category.Name), "category", sharedDomain.CategoryOperationRestore)
+	return category, nil
+}
+
+// restoreName returns name if no live category holds it. Otherwise it fails
+// with ErrCategoryNameTaken, or with autoRename picks "name (restored)",
+// "name (restored 2)", ... instead.
+func (uc *categoryUsecase) restoreName(ctx context.Context, repo repository.CategoryRepository, name string, autoRename bool) (string, error) {
+	candidate := name
+	for n := 1; n <= maxRestoreRenameAttempts; n++ {
+		_, err := repo.GetCategoryByName(ctx, candidate)
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !autoRename {
+			return "", domain.ErrCategoryNameTaken
+		}
+
+		candidate = fmt.Sprintf("%s (restored)", name)
+		if n > 1 {
+			candidate = fmt.Sprintf("%s (restored %d)", name, n)
+		}
+	}
+
+	return "", domain.ErrCategoryNameTaken
+}
@@ -2,49 +2,58 @@ package usecase
 
 import (
 	"category-service/internal/domain"
-	"category-service/internal/grpcservice"
 	"category-service/internal/repository"
+	"category-service/pkg/logger"
 	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/tracing"
 	"context"
+	"errors"
+	"fmt"
 
-	protoBook "category-service/proto/book"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("category-service/internal/usecase")
+
 type categoryUsecase struct {
-	repo       repository.CategoryRepository
-	bookClient *grpcservice.BookGRPCClient
+	repo repository.CategoryRepository
 }
 
-func NewAuthorUsecase(
-	repo repository.CategoryRepository,
-	bookClient *grpcservice.BookGRPCClient,
-) CategoryUsecase {
-	return &categoryUsecase{repo: repo, bookClient: bookClient}
+func NewAuthorUsecase(repo repository.CategoryRepository) CategoryUsecase {
+	return &categoryUsecase{repo: repo}
 }
 
 func (uc *categoryUsecase) CreateCategory(ctx context.Context, req *domain.CreateCategoryRequest) (*sharedDomain.Category, error) {
-	var category *sharedDomain.Category
-	newCategory := &sharedDomain.Category{
-		Name: req.Name,
-	}
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.CreateCategory")
+	defer span.End()
 
-	err := uc.repo.SaveCategory(ctx, newCategory)
-	if err != nil {
-		return nil, err
-	}
-	category = newCategory
+	var category *sharedDomain.Category
 
-	grpcRequest := &protoBook.CategoryData{Id: int64(category.ID), Name: category.Name}
-	_, err = uc.bookClient.SaveCategory(ctx, grpcRequest)
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		newCategory, err := uc.createCategory(ctx, repo, req)
+		if err != nil {
+			return err
+		}
+		category = newCategory
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).Info(fmt.Sprintf("Created category %d", category.ID), "category", sharedDomain.CategoryOperationCreate)
 	return category, nil
 }
 
 func (uc *categoryUsecase) GetAllCategories(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginatedResponse, error) {
-	categories, totalRows, err := uc.repo.GetAllCategories(ctx, req.Page, req.Limit)
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetAllCategories")
+	defer span.End()
+
+	if req.IsCursorMode() {
+		return uc.getCategoriesByCursor(ctx, req)
+	}
+
+	categories, totalRows, err := uc.repo.GetAllCategories(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +69,52 @@ func (uc *categoryUsecase) GetAllCategories(ctx context.Context, req *domain.Pag
 	return paginatedResponse, nil
 }
 
+func (uc *categoryUsecase) getCategoriesByCursor(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginatedResponse, error) {
+	var cursor *domain.CategoryCursor
+	if req.Cursor != "" {
+		decoded, err := domain.DecodeCategoryCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = decoded
+	}
+
+	categories, hasMore, err := uc.repo.GetCategoriesByCursor(ctx, req, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	paginatedResponse := &domain.PaginatedResponse{
+		Data:  categories,
+		Limit: req.Limit,
+	}
+	if len(categories) == 0 {
+		return paginatedResponse, nil
+	}
+
+	backward := cursor != nil && cursor.Backward
+	hasNext := hasMore
+	hasPrev := cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		last := categories[len(categories)-1]
+		paginatedResponse.NextCursor = domain.CategoryCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	if hasPrev {
+		first := categories[0]
+		paginatedResponse.PrevCursor = domain.CategoryCursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true}.Encode()
+	}
+
+	return paginatedResponse, nil
+}
+
 func (uc *categoryUsecase) GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryByID")
+	defer span.End()
+
 	category, err := uc.repo.GetCategoryByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -68,42 +122,278 @@ func (uc *categoryUsecase) GetCategoryByID(ctx context.Context, id uint) (*share
 	return category, nil
 }
 
+func (uc *categoryUsecase) GetCategoriesByIDs(ctx context.Context, ids []uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoriesByIDs")
+	defer span.End()
+
+	return uc.repo.GetCategoriesByIDs(ctx, ids)
+}
+
 func (uc *categoryUsecase) UpdateCategory(ctx context.Context, req *domain.UpdateCategoryRequest) (*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.UpdateCategory")
+	defer span.End()
+
 	var category *sharedDomain.Category
-	existingCategory, err := uc.repo.GetCategoryByID(ctx, req.ID)
+
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		existingCategory, err := uc.updateCategory(ctx, repo, req)
+		if err != nil {
+			return err
+		}
+		category = existingCategory
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Name != nil {
-		existingCategory.Name = *req.Name
+	logger.FromContext(ctx).Info(fmt.Sprintf("Updated category %d to version %d", category.ID, category.Version), "category", sharedDomain.CategoryOperationUpdate)
+	return category, nil
+}
+
+func (uc *categoryUsecase) DeleteCategory(ctx context.Context, req *domain.DeleteCategoryRequest) error {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.DeleteCategory")
+	defer span.End()
+
+	err := uc.repo.WithTransaction(ctx, func(repo repository.CategoryRepository) error {
+		return uc.deleteCategory(ctx, repo, req.ID, req.ExpectedVersion)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info(fmt.Sprintf("Deleted category %d", req.ID), "category", sharedDomain.CategoryOperationDelete)
+	return nil
+}
+
+func (uc *categoryUsecase) GetCategoryChildren(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryChildren")
+	defer span.End()
+
+	if _, err := uc.repo.GetCategoryByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetChildren(ctx, id)
+}
+
+func (uc *categoryUsecase) GetCategoryAncestors(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryAncestors")
+	defer span.End()
+
+	if _, err := uc.repo.GetCategoryByID(ctx, id); err != nil {
+		return nil, err
 	}
+	return uc.repo.GetAncestors(ctx, id)
+}
 
-	err = uc.repo.SaveCategory(ctx, existingCategory)
+func (uc *categoryUsecase) GetCategorySubtree(ctx context.Context, id uint) (*domain.CategoryNode, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategorySubtree")
+	defer span.End()
+
+	categories, err := uc.repo.GetSubtree(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	category = existingCategory
+	if len(categories) == 0 {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	nodes := make(map[uint]*domain.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &domain.CategoryNode{Category: category, Children: []*domain.CategoryNode{}}
+	}
+
+	// Categories are ordered by depth, so every parent is already in nodes.
+	for _, category := range categories {
+		if category.ID == id || category.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[category.ID])
+		}
+	}
+
+	return nodes[id], nil
+}
+
+// createCategory, updateCategory and deleteCategory implement the writes
+// within a transaction owned by the caller, together with their outbox event.
+func (uc *categoryUsecase) createCategory(ctx context.Context, repo repository.CategoryRepository, req *domain.CreateCategoryRequest) (*sharedDomain.Category, error) {
+	actor := domain.ActorFromContext(ctx)
+	newCategory := &sharedDomain.Category{
+		ID:        req.ID,
+		Name:      req.Name,
+		ParentID:  req.ParentID,
+		CreatedBy: actor,
+		UpdatedBy: actor,
+		Version:   1,
+	}
+
+	if req.ParentID != nil {
+		if err := uc.ensureValidParent(ctx, repo, req.ID, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.assignSlug(ctx, repo, newCategory); err != nil {
+		return nil, err
+	}
+	if err := repo.CreateCategory(ctx, newCategory); err != nil {
+		return nil, err
+	}
+	if err := uc.recordHistory(ctx, repo, newCategory.ID, sharedDomain.CategoryOperationCreate, nil, sharedDomain.NewCategorySnapshot(newCategory)); err != nil {
+		return nil, err
+	}
+	if err := uc.enqueueCategorySaved(ctx, repo, newCategory); err != nil {
+		return nil, err
+	}
+
+	return newCategory, nil
+}
 
-	grpcRequest := &protoBook.CategoryData{Id: int64(category.ID), Name: category.Name}
-	_, err = uc.bookClient.SaveCategory(ctx, grpcRequest)
+func (uc *categoryUsecase) updateCategory(ctx context.Context, repo repository.CategoryRepository, req *domain.UpdateCategoryRequest) (*sharedDomain.Category, error) {
+	existingCategory, err := repo.GetCategoryByID(ctx, req.ID)
 	if err != nil {
 		return nil, err
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != existingCategory.Version {
+		return nil, domain.ErrCategoryVersionMismatch
+	}
+	before := sharedDomain.NewCategorySnapshot(existingCategory)
 
-	return category, nil
+	if req.Name != nil {
+		existingCategory.Name = *req.Name
+		if err := uc.assignSlug(ctx, repo, existingCategory); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ParentID.Set {
+		if req.ParentID.Value != nil {
+			if err := uc.ensureValidParent(ctx, repo, existingCategory.ID, *req.ParentID.Value); err != nil {
+				return nil, err
+			}
+		}
+		existingCategory.ParentID = req.ParentID.Value
+	}
+
+	existingCategory.UpdatedBy = domain.ActorFromContext(ctx)
+	if err := repo.SaveCategory(ctx, existingCategory); err != nil {
+		return nil, err
+	}
+	if err := uc.recordHistory(ctx, repo, existingCategory.ID, sharedDomain.CategoryOperationUpdate, before, sharedDomain.NewCategorySnapshot(existingCategory)); err != nil {
+		return nil, err
+	}
+	if err := uc.enqueueCategorySaved(ctx, repo, existingCategory); err != nil {
+		return nil, err
+	}
+
+	return existingCategory, nil
 }
 
-func (uc *categoryUsecase) DeleteCategory(ctx context.Context, id uint) error {
-	err := uc.repo.DeleteCategory(ctx, id)
+func (uc *categoryUsecase) deleteCategory(ctx context.Context, repo repository.CategoryRepository, id uint, expectedVersion *uint) error {
+	// Without the lock a child could be created or moved below the category
+	// between counting its children and deleting it.
+	if err := repo.LockHierarchy(ctx); err != nil {
+		return err
+	}
+
+	category, err := repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if expectedVersion != nil && *expectedVersion != category.Version {
+		return domain.ErrCategoryVersionMismatch
+	}
+
+	children, err := repo.CountChildren(ctx, id)
 	if err != nil {
 		return err
 	}
+	if children > 0 {
+		return domain.ErrCategoryHasChildren
+	}
+
+	if err := repo.DeleteCategory(ctx, id, category.Version); err != nil {
+		return err
+	}
+	if err := uc.recordHistory(ctx, repo, id, sharedDomain.CategoryOperationDelete, sharedDomain.NewCategorySnapshot(category), nil); err != nil {
+		return err
+	}
+	return uc.enqueueCategoryDeleted(ctx, repo, id)
+}
+
+func (uc *categoryUsecase) ensureParentExists(ctx context.Context, repo repository.CategoryRepository, parentID uint) error {
+	if _, err := repo.GetCategoryByID(ctx, parentID); err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return domain.ErrParentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ensureValidParent checks that parentID exists and that making it the parent
+// of categoryID, which is 0 for a new category, neither introduces a cycle in
+// the tree nor nests it deeper than domain.MaxCategoryDepth levels. Changes
+// to parents are serialized, otherwise two concurrent moves such as A under B
+// and B under A could both pass the check and commit a cycle.
+//
+// Ancestors are only walked up to domain.MaxCategoryDepth levels, so a cycle
+// through a deeper chain is not seen; the depth check rejects such a move.
+func (uc *categoryUsecase) ensureValidParent(ctx context.Context, repo repository.CategoryRepository, categoryID, parentID uint) error {
+	if parentID == categoryID {
+		return domain.ErrCategoryCycle
+	}
+
+	if err := repo.LockHierarchy(ctx); err != nil {
+		return err
+	}
+	if err := uc.ensureParentExists(ctx, repo, parentID); err != nil {
+		return err
+	}
 
-	_, err = uc.bookClient.DeleteCategory(ctx, id)
+	ancestors, err := repo.GetAncestors(ctx, parentID)
 	if err != nil {
 		return err
 	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == categoryID {
+			return domain.ErrCategoryCycle
+		}
+	}
+
+	height := 0
+	if categoryID != 0 {
+		height, err = repo.GetSubtreeHeight(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+	}
+	// The category becomes level len(ancestors)+2 counting from the root
+	// as level 1, and its subtree adds height levels below it.
+	if len(ancestors)+2+height > domain.MaxCategoryDepth {
+		return domain.ErrCategoryTooDeep
+	}
 
 	return nil
 }
+
+// enqueueCategorySaved records that the Book service must receive the
+// current state of category.
+func (uc *categoryUsecase) enqueueCategorySaved(ctx context.Context, repo repository.CategoryRepository, category *sharedDomain.Category) error {
+	event, err := sharedDomain.NewCategorySavedEvent(category, tracing.TraceParent(ctx))
+	if err != nil {
+		return err
+	}
+	return repo.SaveOutboxEvent(ctx, event)
+}
+
+// enqueueCategoryDeleted records that the Book service must drop category id.
+func (uc *categoryUsecase) enqueueCategoryDeleted(ctx context.Context, repo repository.CategoryRepository, id uint) error {
+	event, err := sharedDomain.NewCategoryDeletedEvent(id, tracing.TraceParent(ctx))
+	if err != nil {
+		return err
+	}
+	return repo.SaveOutboxEvent(ctx, event)
+}
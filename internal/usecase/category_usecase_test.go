@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"category-service/internal/domain"
+	"category-service/internal/repository"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+// treeRepository keeps a category tree as parent links. Its walks stop after
+// domain.MaxCategoryDepth levels like the SQL ones. Methods the tests do not
+// use panic through the nil embedded interface.
+type treeRepository struct {
+	repository.CategoryRepository
+
+	parents map[uint]uint // 0 for roots
+	locks   int
+}
+
+// newChains returns a repository holding the chain 1 <- 2 <- ... <- n and,
+// unless m is 0, the separate chain 1001 <- 1002 <- ... <- 1000+m.
+func newChains(n, m uint) *treeRepository {
+	r := &treeRepository{parents: map[uint]uint{}}
+	for id := uint(1); id <= n; id++ {
+		r.parents[id] = id - 1
+	}
+	for id := uint(1); id <= m; id++ {
+		r.parents[1000+id] = 1000 + id - 1
+	}
+	if m > 0 {
+		r.parents[1001] = 0
+	}
+	return r
+}
+
+func (r *treeRepository) LockHierarchy(ctx context.Context) error {
+	r.locks++
+	return nil
+}
+
+func (r *treeRepository) GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error) {
+	if _, ok := r.parents[id]; !ok {
+		return nil, domain.ErrCategoryNotFound
+	}
+	return &sharedDomain.Category{ID: id}, nil
+}
+
+func (r *treeRepository) GetAncestors(ctx context.Context, id uint) ([]*sharedDomain.Category, error) {
+	var ancestors []*sharedDomain.Category
+	for parent := r.parents[id]; parent != 0 && len(ancestors) < domain.MaxCategoryDepth; parent = r.parents[parent] {
+		ancestors = append([]*sharedDomain.Category{{ID: parent}}, ancestors...)
+	}
+	return ancestors, nil
+}
+
+func (r *treeRepository) GetSubtreeHeight(ctx context.Context, id uint) (int, error) {
+	if _, ok := r.parents[id]; !ok {
+		return 0, nil
+	}
+	level := []uint{id}
+	height := 0
+	for height < domain.MaxCategoryDepth {
+		var next []uint
+		for child, parent := range r.parents {
+			for _, p := range level {
+				if parent == p {
+					next = append(next, child)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		level = next
+		height++
+	}
+	return height, nil
+}
+
+func TestEnsureValidParent(t *testing.T) {
+	tests := []struct {
+		name       string
+		chain      uint
+		branch     uint
+		categoryID uint
+		parentID   uint
+		wantErr    error
+	}{
+		{name: "leaf below the root", chain: 3, categoryID: 3, parentID: 1},
+		{name: "new category", chain: 3, parentID: 3},
+		{name: "new category at the last level", chain: domain.MaxCategoryDepth - 1, parentID: domain.MaxCategoryDepth - 1},
+		{name: "new category below the last level", chain: domain.MaxCategoryDepth, parentID: domain.MaxCategoryDepth, wantErr: domain.ErrCategoryTooDeep},
+		{name: "below itself", chain: 3, categoryID: 2, parentID: 2, wantErr: domain.ErrCategoryCycle},
+		{name: "below its descendant", chain: 3, categoryID: 1, parentID: 3, wantErr: domain.ErrCategoryCycle},
+		{name: "subtree fits below its new parent", chain: 40, branch: 30, categoryID: 10, parentID: 1030},
+		{name: "subtree too deep for its new parent", chain: 40, branch: 35, categoryID: 10, parentID: 1035, wantErr: domain.ErrCategoryTooDeep},
+		{name: "missing parent", chain: 3, categoryID: 1, parentID: 9, wantErr: domain.ErrParentNotFound},
+		{
+			// The descendant is beyond the levels the ancestor walk reaches,
+			// so the cycle itself is not seen.
+			name:       "root below a descendant deeper than the walk",
+			chain:      domain.MaxCategoryDepth + 36,
+			categoryID: 1,
+			parentID:   domain.MaxCategoryDepth + 36,
+			wantErr:    domain.ErrCategoryTooDeep,
+		},
+		{
+			name:       "root below a descendant within the walk of a long chain",
+			chain:      domain.MaxCategoryDepth + 36,
+			categoryID: 1,
+			parentID:   30,
+			wantErr:    domain.ErrCategoryCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newChains(tt.chain, tt.branch)
+			uc := &categoryUsecase{repo: repo}
+
+			err := uc.ensureValidParent(context.Background(), repo, tt.categoryID, tt.parentID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ensureValidParent(%d, %d) error = %v, want %v", tt.categoryID, tt.parentID, err, tt.wantErr)
+			}
+			if repo.locks == 0 && tt.categoryID != tt.parentID {
+				t.Error("ensureValidParent() did not lock the hierarchy")
+			}
+		})
+	}
+}
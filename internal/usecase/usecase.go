@@ -4,12 +4,31 @@ import (
 	"category-service/internal/domain"
 	sharedDomain "category-service/pkg/shared/domain"
 	"context"
+	"time"
 )
 
 type CategoryUsecase interface {
 	CreateCategory(ctx context.Context, req *domain.CreateCategoryRequest) (*sharedDomain.Category, error)
 	GetAllCategories(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginatedResponse, error)
 	GetCategoryByID(ctx context.Context, id uint) (*sharedDomain.Category, error)
+	// GetCategoryBySlug also resolves former slugs, reporting them as redirected.
+	GetCategoryBySlug(ctx context.Context, slug string) (*sharedDomain.Category, bool, error)
+	GetCategoriesByIDs(ctx context.Context, ids []uint) ([]*sharedDomain.Category, error)
 	UpdateCategory(ctx context.Context, req *domain.UpdateCategoryRequest) (*sharedDomain.Category, error)
-	DeleteCategory(ctx context.Context, id uint) error
+	DeleteCategory(ctx context.Context, req *domain.DeleteCategoryRequest) error
+	GetCategoryHistory(ctx context.Context, req *domain.CategoryHistoryRequest) (*domain.PaginatedResponse, error)
+	GetTrash(ctx context.Context, req *domain.TrashRequest) (*domain.PaginatedResponse, error)
+	RestoreCategory(ctx context.Context, req *domain.RestoreCategoryRequest) (*sharedDomain.Category, error)
+	PurgeDeletedCategories(ctx context.Context, deletedBefore time.Time, batchSize int) (int, error)
+	PurgeCategory(ctx context.Context, id uint) error
+	BatchCategories(ctx context.Context, req *domain.BatchCategoryRequest) (*domain.BatchCategoryResponse, error)
+
+	ExportCategories(ctx context.Context, fn func(row *domain.CategoryExportRow) error) error
+	ImportCategories(ctx context.Context, source domain.CategoryImportSource, req *domain.ImportCategoriesRequest) (*domain.ImportReport, error)
+
+	GetCategoryChildren(ctx context.Context, id uint) ([]*sharedDomain.Category, error)
+	GetCategoryAncestors(ctx context.Context, id uint) ([]*sharedDomain.Category, error)
+	GetCategorySubtree(ctx context.Context, id uint) (*domain.CategoryNode, error)
+
+	BackfillSlugs(ctx context.Context) (int, error)
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"category-service/config"
+	"category-service/config/key"
+	"category-service/internal/domain"
+	"category-service/pkg/token"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// runIssueToken implements the "token" subcommand, which signs a token for
+// local development. It requires JWT_PRIVATE_KEY_PATH.
+func runIssueToken(args []string) int {
+	flags := flag.NewFlagSet("token", flag.ContinueOnError)
+	userID := flags.Uint("user", 1, "user ID to issue the token for")
+	roles := flags.String("roles", "", "comma-separated roles")
+	scope := flags.String("scope", "", "space-separated scopes")
+	ttl := flags.Duration("ttl", time.Hour, "token lifetime")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// Only the key settings are needed, so the rest is not validated.
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if cfg.GetJWTPrivateKeyPath() == "" {
+		fmt.Fprintln(os.Stderr, "JWT_PRIVATE_KEY_PATH is not set, signing tokens is disabled")
+		return 1
+	}
+
+	privateKey, err := key.LoadRSAPrivateKey(cfg.GetJWTPrivateKeyPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load RSA private key: %v\n", err)
+		return 1
+	}
+
+	claims := domain.TokenClaims{UserID: *userID, Scope: *scope}
+	if *roles != "" {
+		claims.Roles = strings.Split(*roles, ",")
+	}
+
+	signedToken, err := token.NewSigner(privateKey, cfg.GetJWTSigningKeyID()).GenerateToken(claims, *ttl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to sign token: %v\n", err)
+		return 1
+	}
+
+	fmt.Println(signedToken)
+	return 0
+}
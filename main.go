@@ -4,15 +4,21 @@ import (
 	"category-service/config"
 	"category-service/config/key"
 	deliveryG "category-service/internal/delivery/http"
+	"category-service/internal/domain"
 	"category-service/internal/repository"
 	"category-service/internal/usecase"
 	"category-service/pkg/database"
 	"category-service/pkg/logger"
+	"category-service/pkg/metrics"
 	"category-service/pkg/middleware"
 	sharedDomain "category-service/pkg/shared/domain"
 	"category-service/pkg/token"
+	"category-service/pkg/tracing"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,15 +27,49 @@ import (
 	"time"
 
 	"category-service/internal/grpcservice"
+	"category-service/internal/job"
+	protoCategory "category-service/proto/category"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 func main() {
-	cfg := config.LoadConfig()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			os.Exit(runReconcile(os.Args[2:]))
+		case "token":
+			os.Exit(runIssueToken(os.Args[2:]))
+		}
+	}
+
+	cfg, err := config.LoadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	logger := logger.NewLogger("category-service", logrus.DebugLevel, os.Stdout)
+	// Validated by LoadConfig.
+	logLevel, _ := logrus.ParseLevel(cfg.GetLogLevel())
+	logger := logger.NewLogger("category-service", logLevel, os.Stdout)
+	logger.SetFormat(cfg.GetLogFormat())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName:  "category-service",
+		Exporter:     cfg.GetTracingExporter(),
+		OTLPEndpoint: cfg.GetTracingOTLPEndpoint(),
+		OTLPInsecure: cfg.GetTracingOTLPInsecure(),
+		SampleRatio:  cfg.GetTracingSampleRatio(),
+	})
+	if err != nil {
+		logger.Panic(fmt.Sprintf("Failed to set up tracing: %v", err), "tracing", "error")
+	}
 
 	_, cancel := context.WithTimeout(context.Background(), 1*time.Minute) // timeout to shutdown server and init configuration
 	defer func() {
@@ -48,51 +88,219 @@ func main() {
 
 	if err := db.AutoMigrate(
 		&sharedDomain.Category{},
+		&sharedDomain.OutboxEvent{},
+		&sharedDomain.CategorySlugRedirect{},
+		&sharedDomain.CategoryHistory{},
+		&sharedDomain.IdempotencyKey{},
+		&sharedDomain.RevokedToken{},
+		&sharedDomain.UserTokenRevocation{},
 	); err != nil {
 		logger.Panic(fmt.Sprintf("Failed to perform migration: %v", err), "migration", "error")
 	}
 
-	privateKeyPath := "config/key/private_key.pem"
-	publicKeyPath := "config/key/public_key.pem"
+	// Verify tokens against the JWKS of the auth service if one is
+	// configured, which allows it to rotate keys without redeploying us.
+	// Otherwise fall back to its PEM public key. No private key is needed.
+	var jwks *token.JWKS
+	var verificationKeys token.KeyProvider
+	if source := cfg.GetJWKSSource(); source != "" {
+		var err error
+		jwks, err = token.NewJWKS(context.Background(), source, logger)
+		if err != nil {
+			logger.Panic(fmt.Sprintf("Failed to load JWKS: %v", err), "load jwks", "error")
+		}
+		verificationKeys = jwks
+	} else {
+		publicKey, err := key.LoadRSAPublicKey(cfg.GetJWTPublicKeyPath())
+		if err != nil {
+			logger.Panic(fmt.Sprintf("Failed to load RSA public key: %v", err), "load rsa", "error")
+		}
+		verificationKeys = token.NewStaticKeyProvider(publicKey)
+	}
 
-	privateKey, publicKey, err := key.LoadRSAKeys(privateKeyPath, publicKeyPath)
+	revocationList, err := token.NewRevocationList(context.Background(), repository.NewRevocationRepository(db.GetDB()), logger)
 	if err != nil {
-		logger.Panic(fmt.Sprintf("Failed to load RSA keys: %v", err), "load rsa", "error")
+		logger.Panic(fmt.Sprintf("Failed to load token revocations: %v", err), "revocation", "error")
 	}
 
-	jwtService := token.NewJWT(publicKey, privateKey)
+	jwtService := token.NewRevocationVerifier(token.NewVerifier(verificationKeys, token.VerifierOptions{
+		Issuers:    cfg.GetJWTIssuers(),
+		Audiences:  cfg.GetJWTAudiences(),
+		Algorithms: cfg.GetJWTAlgorithms(),
+		Leeway:     time.Duration(cfg.GetJWTLeewaySeconds()) * time.Second,
+	}), revocationList)
 
-	bookClient := grpcservice.NewBookGRPCClient(cfg.GetBookGRPCHost() + ":" + cfg.GetBookGRPCPort())
+	bookClient := grpcservice.NewBookGRPCClient(net.JoinHostPort(cfg.GetBookGRPCHost(), cfg.GetBookGRPCPort()), bookDialOptions(cfg)...)
 
 	// Setup repository, usecase, dan handler
 	categoryRepo := repository.NewAuthorRepository(db.GetDB())
-	categoryUsecase := usecase.NewAuthorUsecase(categoryRepo, bookClient)
+	categoryUsecase := usecase.NewAuthorUsecase(categoryRepo)
 	categoryHandler := deliveryG.NewCategoryHandler(categoryUsecase)
 
+	// A failed backfill only leaves some categories without a slug, so it is
+	// retried at the next start instead of stopping this one.
+	if backfilled, err := categoryUsecase.BackfillSlugs(context.Background()); err != nil {
+		logger.Error(fmt.Sprintf("Failed to backfill category slugs, will retry at next start: %v", err), "migration", "slug")
+	} else if backfilled > 0 {
+		logger.Info(fmt.Sprintf("Backfilled slugs for %d categories", backfilled), "migration", "slug")
+	}
+
+	// Permanently delete categories that stayed in the trash past retention
+	purgerConfig := job.DefaultCategoryPurgerConfig()
+	purgerConfig.Retention = time.Duration(cfg.GetCategoryRetentionDays()) * 24 * time.Hour
+	categoryPurger := job.NewCategoryPurger(categoryUsecase, logger, purgerConfig)
+
+	outboxRepo := repository.NewOutboxRepository(db.GetDB())
+	reconciler := grpcservice.NewCategoryReconciler(categoryRepo, outboxRepo, logger)
+	adminHandler := deliveryG.NewAdminHandler(categoryUsecase, reconciler, categoryPurger)
+
+	// Deliver category changes to the Book service in the background
+	dispatcherConfig := grpcservice.DefaultOutboxDispatcherConfig()
+	dispatcherConfig.CallTimeout = time.Duration(cfg.GetBookGRPCCallTimeoutSeconds()) * time.Second
+	outboxDispatcher := grpcservice.NewOutboxDispatcher(outboxRepo, bookClient, logger, dispatcherConfig)
+
+	backgroundCtx, backgroundCancel := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		outboxDispatcher.Run(backgroundCtx)
+	}()
+
+	outboxCleanerDone := make(chan struct{})
+	go func() {
+		defer close(outboxCleanerDone)
+		outboxRetention := time.Duration(cfg.GetOutboxRetentionDays()) * 24 * time.Hour
+		job.NewOutboxCleaner(outboxRepo, logger, time.Hour, outboxRetention).Run(backgroundCtx)
+	}()
+
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		categoryPurger.Run(backgroundCtx)
+	}()
+
+	if jwks != nil {
+		go jwks.Run(backgroundCtx, time.Duration(cfg.GetJWKSRefreshMinutes())*time.Minute)
+	}
+	go revocationList.Run(backgroundCtx, time.Duration(cfg.GetRevocationRefreshSeconds())*time.Second)
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db.GetDB())
+	idempotencyTTL := time.Duration(cfg.GetIdempotencyKeyTTLHours()) * time.Hour
+	idempotencyLease := time.Duration(cfg.GetIdempotencyKeyLeaseSeconds()) * time.Second
+	idempotency := middleware.IdempotencyMiddleware(idempotencyRepo, idempotencyTTL, idempotencyLease)
+
+	cleanerDone := make(chan struct{})
+	go func() {
+		defer close(cleanerDone)
+		job.NewIdempotencyKeyCleaner(idempotencyRepo, logger, time.Hour).Run(backgroundCtx)
+	}()
+
 	// Setup routes
-	httpServer := gin.Default()
+	httpServer := gin.New()
 
-	categoryRoutes := httpServer.Group("/categories", middleware.JWTAuthMiddleware(jwtService))
+	// Probes are registered before the global middleware, so they are kept
+	// out of the access log, metrics and traces.
+	// Only the database fails readiness; book updates are queued in the
+	// outbox while the book service is down.
+	healthHandler := deliveryG.NewHealthHandler(map[string]deliveryG.HealthChecker{
+		"database": db,
+	}, map[string]deliveryG.HealthChecker{
+		"book_service": bookClient,
+	})
+	httpServer.GET("/healthz", healthHandler.Liveness)
+	httpServer.GET("/readyz", healthHandler.Readiness)
+
+	httpServer.Use(middleware.RequestIDMiddleware(logger), middleware.TracingMiddleware(), middleware.AccessLogMiddleware(), middleware.MetricsMiddleware(), gin.Recovery())
+
+	metrics.Registry.MustRegister(metrics.NewCategoryCollector(categoryRepo), metrics.NewOutboxCollector(outboxRepo))
+	metricsHandlers := []gin.HandlerFunc{gin.WrapH(metrics.Handler())}
+	if cfg.GetMetricsBasicAuth() {
+		metricsHandlers = append([]gin.HandlerFunc{middleware.BasicAuthMiddleware(cfg)}, metricsHandlers...)
+	}
+	httpServer.GET("/metrics", metricsHandlers...)
+
+	rolePermissions := domain.RolePermissions(cfg.GetRolePermissions())
+	canRead := middleware.RequirePermission(domain.PermissionCategoriesRead)
+	canWrite := middleware.RequirePermission(domain.PermissionCategoriesWrite)
+	canAdmin := middleware.RequirePermission(domain.PermissionCategoriesAdmin)
+
+	categoryRoutes := httpServer.Group("/categories", middleware.JWTAuthMiddleware(jwtService), middleware.AuthorizationMiddleware(rolePermissions))
 	{
-		categoryRoutes.POST("", categoryHandler.CreateCategory)
-		categoryRoutes.GET("", categoryHandler.GetAllCategories)
-		categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID)
-		categoryRoutes.PATCH("/:id", categoryHandler.UpdateCategory)
-		categoryRoutes.DELETE("/:id", categoryHandler.DeleteCategory)
+		categoryRoutes.POST("", canWrite, idempotency, categoryHandler.CreateCategory)
+		categoryRoutes.GET("", canRead, categoryHandler.GetAllCategories)
+		categoryRoutes.POST("/batch", canWrite, idempotency, categoryHandler.BatchCategories)
+		categoryRoutes.GET("/export", canRead, categoryHandler.ExportCategories)
+		categoryRoutes.POST("/import", canWrite, categoryHandler.ImportCategories)
+		categoryRoutes.GET("/slug/:slug", canRead, categoryHandler.GetCategoryBySlug)
+		categoryRoutes.GET("/trash", canAdmin, categoryHandler.GetTrash)
+		categoryRoutes.GET("/:id", canRead, categoryHandler.GetCategoryByID)
+		categoryRoutes.GET("/:id/children", canRead, categoryHandler.GetCategoryChildren)
+		categoryRoutes.GET("/:id/ancestors", canRead, categoryHandler.GetCategoryAncestors)
+		categoryRoutes.GET("/:id/subtree", canRead, categoryHandler.GetCategorySubtree)
+		categoryRoutes.GET("/:id/history", canRead, categoryHandler.GetCategoryHistory)
+		categoryRoutes.PATCH("/:id", canWrite, idempotency, categoryHandler.UpdateCategory)
+		categoryRoutes.DELETE("/:id", canWrite, idempotency, categoryHandler.DeleteCategory)
+		categoryRoutes.POST("/:id/restore", canAdmin, idempotency, categoryHandler.RestoreCategory)
 	}
 
-	httpPort := cfg.GetHTTPPort()
-	if httpPort == "" {
-		httpPort = "8080"
+	adminRoutes := httpServer.Group("/admin", middleware.BasicAuthMiddleware(cfg))
+	{
+		adminRoutes.GET("/categories", adminHandler.GetAllCategories)
+		adminRoutes.POST("/categories/purge", adminHandler.PurgeDeletedCategories)
+		adminRoutes.DELETE("/categories/:id", adminHandler.PurgeCategory)
+		adminRoutes.POST("/reconcile", adminHandler.ReconcileBookService)
+		adminRoutes.GET("/reconcile", adminHandler.GetReconcileRun)
+
+		revocationHandler := deliveryG.NewRevocationHandler(revocationList)
+		adminRoutes.GET("/revocations", revocationHandler.GetRevocations)
+		adminRoutes.POST("/revocations/tokens", revocationHandler.RevokeToken)
+		adminRoutes.DELETE("/revocations/tokens/:jti", revocationHandler.UnrevokeToken)
+		adminRoutes.POST("/revocations/users", revocationHandler.RevokeUserTokens)
+		adminRoutes.DELETE("/revocations/users/:userId", revocationHandler.UnrevokeUserTokens)
 	}
 
+	httpAddr := net.JoinHostPort(cfg.GetHTTPHost(), cfg.GetHTTPPort())
 	httpSrv := &http.Server{
-		Addr:    ":" + httpPort,
-		Handler: httpServer,
+		Addr:              httpAddr,
+		Handler:           httpServer,
+		ReadHeaderTimeout: time.Duration(cfg.GetHTTPReadHeaderTimeoutSeconds()) * time.Second,
+		ReadTimeout:       time.Duration(cfg.GetHTTPReadTimeoutSeconds()) * time.Second,
+		WriteTimeout:      time.Duration(cfg.GetHTTPWriteTimeoutSeconds()) * time.Second,
+		IdleTimeout:       time.Duration(cfg.GetHTTPIdleTimeoutSeconds()) * time.Second,
+	}
+
+	grpcAddr := net.JoinHostPort(cfg.GetGRPCHost(), cfg.GetGRPCPort())
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		logger.Panic(fmt.Sprintf("Failed to listen on gRPC port: %v", err), "grpc_server", "error")
 	}
 
+	grpcSrv := grpc.NewServer(
+		grpc.MaxRecvMsgSize(cfg.GetGRPCMaxRecvMessageBytes()),
+		grpc.MaxSendMsgSize(cfg.GetGRPCMaxSendMessageBytes()),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    time.Duration(cfg.GetGRPCKeepaliveSeconds()) * time.Second,
+			Timeout: time.Duration(cfg.GetGRPCKeepaliveTimeoutSeconds()) * time.Second,
+		}),
+		grpc.ChainUnaryInterceptor(
+			grpcservice.RequestLoggingUnaryInterceptor(logger),
+			grpcservice.RecoveryUnaryInterceptor(),
+			grpcservice.TracingUnaryInterceptor(),
+			grpcservice.JWTUnaryInterceptor(jwtService),
+			grpcservice.PermissionUnaryInterceptor(rolePermissions),
+		),
+	)
+	protoCategory.RegisterCategoryServiceServer(grpcSrv, grpcservice.NewCategoryGRPCServer(categoryUsecase))
+
+	go func() {
+		logger.Info("gRPC Server listening on "+grpcAddr, "server_startup", "addr:"+grpcAddr)
+		if err := grpcSrv.Serve(grpcListener); err != nil && err != grpc.ErrServerStopped {
+			logger.Panic(fmt.Sprintf("gRPC server error: %v", err), "grpc_server", "error")
+		}
+	}()
+
 	go func() {
-		logger.Info("HTTP Server listening on port "+httpPort, "server_startup", "port:"+httpPort)
+		logger.Info("HTTP Server listening on "+httpAddr, "server_startup", "addr:"+httpAddr)
 		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Panic(fmt.Sprintf("HTTP server error: %v", err), "http_server", "error")
 		}
@@ -104,15 +312,74 @@ func main() {
 	<-sigChan
 	logger.Info("Shutdown signal received, shutting down gracefully...", "", "")
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	healthHandler.SetShuttingDown()
+	if drain := time.Duration(cfg.GetShutdownDrainSeconds()) * time.Second; drain > 0 {
+		logger.Info(fmt.Sprintf("Draining for %s before stopping servers...", drain), "", "")
+		time.Sleep(drain)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.GetShutdownTimeoutSeconds())*time.Second)
 	defer shutdownCancel()
 
 	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
 		logger.Error(fmt.Sprintf("HTTP server shutdown error: %v", err), "", "")
 	}
 
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		logger.Warn("gRPC server did not stop in time, forcing shutdown", "", "")
+		grpcSrv.Stop()
+	}
+
+	logger.Info("Stopping background workers...", "", "")
+	backgroundCancel()
+	reconciler.Stop(shutdownCtx)
+	select {
+	case <-dispatcherDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Outbox dispatcher did not stop in time", "", "")
+	}
+	select {
+	case <-outboxCleanerDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Outbox cleaner did not stop in time", "", "")
+	}
+	select {
+	case <-purgerDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Category purger did not stop in time", "", "")
+	}
+	select {
+	case <-cleanerDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Idempotency key cleaner did not stop in time", "", "")
+	}
+
+	logger.Info("Flushing traces...", "", "")
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error(fmt.Sprintf("Tracing shutdown error: %v", err), "", "")
+	}
+
 	logger.Info("Closing database connection...", "", "")
 	db.Close()
 
 	logger.Info("Servers shut down successfully", "", "")
 }
+
+// bookDialOptions returns the connection options of the Book client that
+// depend on the configuration.
+func bookDialOptions(cfg config.ConfigProvider) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if seconds := cfg.GetBookGRPCKeepaliveSeconds(); seconds > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time: time.Duration(seconds) * time.Second,
+		}))
+	}
+	return opts
+}
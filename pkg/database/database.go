@@ -2,9 +2,13 @@ package database
 
 import (
 	"category-service/config"
+	"category-service/pkg/metrics"
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -14,6 +18,8 @@ type Database interface {
 	Connect(cfg config.ConfigProvider) error
 	AutoMigrate(models ...interface{}) error
 	GetDB() *gorm.DB
+	// Ping checks that the database is reachable.
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -26,20 +32,31 @@ func (g *GormDatabase) Connect(cfg config.ConfigProvider) error {
 		cfg.GetDBHost(), cfg.GetDBPort(), cfg.GetDBUser(), cfg.GetDBPassword(), cfg.GetDBName(), cfg.GetSSLMode())
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: NewGormLogger(logger.Info),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(metricsPlugin{}); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return fmt.Errorf("failed to register database tracing: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(cfg.GetDBMaxOpenConns())
+	sqlDB.SetMaxIdleConns(cfg.GetDBMaxIdleConns())
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.GetDBConnMaxLifetimeMinutes()) * time.Minute)
+
+	if err := metrics.Registry.Register(collectors.NewDBStatsCollector(sqlDB, cfg.GetDBName())); err != nil {
+		return fmt.Errorf("failed to register database pool metrics: %w", err)
+	}
 
 	g.db = db
 	return nil
@@ -47,6 +64,9 @@ func (g *GormDatabase) Connect(cfg config.ConfigProvider) error {
 
 func (g *GormDatabase) AutoMigrate(models ...interface{}) error {
 	if len(models) > 0 {
+		if err := migrateLegacySchema(g.db); err != nil {
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
 		if err := g.db.AutoMigrate(models...); err != nil {
 			return fmt.Errorf("failed to migrate: %w", err)
 		}
@@ -58,6 +78,17 @@ func (g *GormDatabase) GetDB() *gorm.DB {
 	return g.db
 }
 
+func (g *GormDatabase) Ping(ctx context.Context) error {
+	if g.db == nil {
+		return errors.New("database is not connected")
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 func (g *GormDatabase) Close() error {
 	if g.db != nil {
 		sqlDB, err := g.db.DB()
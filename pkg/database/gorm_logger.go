@@ -0,0 +1,78 @@
+package database
+
+import (
+	"category-service/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// slowQueryThreshold is the duration above which queries are logged as warnings.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger writes GORM logs through the logger of the query context, so
+// queries run while serving a request carry its request and user ID.
+type gormLogger struct {
+	level gormlogger.LogLevel
+}
+
+// NewGormLogger logs failed and slow queries, and with gormlogger.Info every
+// query at debug level.
+func NewGormLogger(level gormlogger.LogLevel) gormlogger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) Info(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log(ctx).Info(fmt.Sprintf(message, data...), "database", "info")
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log(ctx).Warn(fmt.Sprintf(message, data...), "database", "warn")
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log(ctx).Error(fmt.Sprintf(message, data...), "database", "error")
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	log := l.log(ctx).With(logger.Fields{
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+		"rows":        rows,
+	})
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Error(fmt.Sprintf("Query failed: %v: %s", err, sql), "database", "query")
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		log.Warn("Slow query: "+sql, "database", "query")
+	case l.level >= gormlogger.Info:
+		log.Debug(sql, "database", "query")
+	}
+}
+
+// log returns the context logger annotated with the repository code that ran
+// the query, since the caller field would otherwise point into GORM.
+func (l *gormLogger) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx).With(logger.Fields{"source": utils.FileWithLineNum()})
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"category-service/pkg/metrics"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// metricsPlugin records the duration of every query in metrics.
+type metricsPlugin struct{}
+
+func (metricsPlugin) Name() string { return "metrics" }
+
+func (metricsPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("metrics:before_create", startQuery),
+		callbacks.Create().After("*").Register("metrics:after_create", observeQuery("create")),
+		callbacks.Query().Before("*").Register("metrics:before_query", startQuery),
+		callbacks.Query().After("*").Register("metrics:after_query", observeQuery("query")),
+		callbacks.Update().Before("*").Register("metrics:before_update", startQuery),
+		callbacks.Update().After("*").Register("metrics:after_update", observeQuery("update")),
+		callbacks.Delete().Before("*").Register("metrics:before_delete", startQuery),
+		callbacks.Delete().After("*").Register("metrics:after_delete", observeQuery("delete")),
+		callbacks.Row().Before("*").Register("metrics:before_row", startQuery),
+		callbacks.Row().After("*").Register("metrics:after_row", observeQuery("row")),
+		callbacks.Raw().Before("*").Register("metrics:before_raw", startQuery),
+		callbacks.Raw().After("*").Register("metrics:after_raw", observeQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		outcome := "success"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			outcome = "error"
+		}
+		metrics.ObserveDBQuery(operation, table, outcome, time.Since(start))
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrateLegacySchema makes schema changes that AutoMigrate cannot make
+// itself. It runs before AutoMigrate and does nothing on a new database.
+func migrateLegacySchema(db *gorm.DB) error {
+	return dropCategoryNameUniqueConstraints(db)
+}
+
+// dropCategoryNameUniqueConstraints removes the unique constraint that
+// categories.name had before names only had to be unique among live
+// categories. Its name depends on the GORM version that created the table
+// (categories_name_key or uni_categories_name), so it is looked up instead
+// of guessed; a surviving constraint would make reusing the name of a
+// trashed category fail.
+func dropCategoryNameUniqueConstraints(db *gorm.DB) error {
+	var names []string
+	err := db.Raw(`
+		SELECT con.conname FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+		JOIN pg_attribute att ON att.attrelid = rel.oid AND att.attname = 'name'
+		WHERE con.contype = 'u'
+			AND rel.relname = 'categories'
+			AND nsp.nspname = current_schema()
+			AND con.conkey = ARRAY[att.attnum]`).Scan(&names).Error
+	if err != nil {
+		return fmt.Errorf("failed to look up unique constraints of categories.name: %w", err)
+	}
+
+	for _, name := range names {
+		if err := db.Exec("ALTER TABLE categories DROP CONSTRAINT ?", clause.Column{Name: name}).Error; err != nil {
+			return fmt.Errorf("failed to drop unique constraint %s of categories.name: %w", name, err)
+		}
+	}
+	return nil
+}
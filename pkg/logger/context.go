@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying logger, so that code serving a
+// request logs with its request-scoped fields.
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger set by WithContext, or the process-wide
+// logger if there is none.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	if instance != nil {
+		return instance
+	}
+	return NewLogger("category-service", logrus.InfoLevel, os.Stdout)
+}
+
+// AddFields returns a copy of ctx whose logger adds fields to every entry.
+func AddFields(ctx context.Context, fields Fields) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(fields))
+}
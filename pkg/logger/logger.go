@@ -5,6 +5,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,11 +18,25 @@ type Logger interface {
 	Fatal(message, event, key string)
 	Panic(message, event, key string)
 	SetOutput(output *os.File)
+	SetFormat(format string)
+	// With returns a logger that adds fields to every entry, e.g. the ID of
+	// the request being served.
+	With(fields Fields) Logger
 }
 
+// Fields are additional key/value pairs of log entries.
+type Fields map[string]interface{}
+
+// Output formats accepted by SetFormat.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
 type LoggerImpl struct {
 	logger  *logrus.Logger
 	appName string
+	fields  logrus.Fields
 }
 
 var (
@@ -52,13 +67,40 @@ func (l *LoggerImpl) SetOutput(output *os.File) {
 	l.logger.SetOutput(output)
 }
 
+// SetFormat switches between FormatText and FormatJSON; JSON entries carry
+// their fields as top-level keys, so log collectors can index them.
+func (l *LoggerImpl) SetFormat(format string) {
+	if format == FormatJSON {
+		l.logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+		return
+	}
+	l.logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		DisableColors: false,
+		ForceQuote:    true,
+	})
+}
+
+func (l *LoggerImpl) With(fields Fields) Logger {
+	merged := make(logrus.Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &LoggerImpl{logger: l.logger, appName: l.appName, fields: merged}
+}
+
 func (l *LoggerImpl) logWithFields(level logrus.Level, message, event, key string) {
-	fields := logrus.Fields{
-		"caller": l.getCallerInfo(),
-		"topic":  l.appName,
-		"event":  event,
-		"key":    key,
+	fields := logrus.Fields{}
+	for k, v := range l.fields {
+		fields[k] = v
 	}
+	fields["caller"] = l.getCallerInfo()
+	fields["topic"] = l.appName
+	fields["event"] = event
+	fields["key"] = key
 
 	entry := l.logger.WithFields(fields)
 	switch level {
@@ -77,8 +119,10 @@ func (l *LoggerImpl) logWithFields(level logrus.Level, message, event, key strin
 	}
 }
 
+// getCallerInfo skips itself, logWithFields and the level method to report
+// the code that logged.
 func (l *LoggerImpl) getCallerInfo() string {
-	if pc, file, line, ok := runtime.Caller(2); ok {
+	if pc, file, line, ok := runtime.Caller(3); ok {
 		return fmt.Sprintf("%s:%d %s", file, line, runtime.FuncForPC(pc).Name())
 	}
 	return "Unknown Caller"
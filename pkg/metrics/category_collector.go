@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// categoryCountTimeout bounds the query run on every scrape.
+const categoryCountTimeout = 5 * time.Second
+
+// CategoryCounter counts the live and the soft-deleted categories.
+type CategoryCounter interface {
+	CountCategories(ctx context.Context) (live, deleted int64, err error)
+}
+
+var categoriesDesc = prometheus.NewDesc(
+	"categories",
+	"Number of categories, by state (live or deleted).",
+	[]string{"state"}, nil,
+)
+
+// categoryCollector reads the category counts at scrape time, so they are
+// correct across all instances of the service.
+type categoryCollector struct {
+	counter CategoryCounter
+}
+
+// NewCategoryCollector reports the number of live and soft-deleted
+// categories as the "categories" gauge.
+func NewCategoryCollector(counter CategoryCounter) prometheus.Collector {
+	return &categoryCollector{counter: counter}
+}
+
+func (c *categoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- categoriesDesc
+}
+
+func (c *categoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), categoryCountTimeout)
+	defer cancel()
+
+	live, deleted, err := c.counter.CountCategories(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(categoriesDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(categoriesDesc, prometheus.GaugeValue, float64(live), "live")
+	ch <- prometheus.MustNewConstMetric(categoriesDesc, prometheus.GaugeValue, float64(deleted), "deleted")
+}
@@ -0,0 +1,86 @@
+// Package metrics holds the Prometheus collectors of the service, registered
+// on Registry and exposed by Handler.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric of the service, plus the Go runtime and process
+// metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests, by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of database queries, by operation, table and outcome.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "outcome"})
+
+	bookClientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "book_client_requests_total",
+		Help: "Calls to the Book service, by RPC and gRPC status code.",
+	}, []string{"rpc", "code"})
+
+	bookClientRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "book_client_request_duration_seconds",
+		Help:    "Latency of calls to the Book service, by RPC.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"rpc"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		dbQueryDuration,
+		bookClientRequests,
+		bookClientRequestDuration,
+	)
+}
+
+// Handler serves the metrics of Registry in the Prometheus text format. A
+// failing collector, e.g. because the database is down, only drops its own
+// metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+		Registry:      Registry,
+	})
+}
+
+// ObserveHTTPRequest records a handled HTTP request. route is the route
+// pattern, not the path, to keep the number of series bounded.
+func ObserveHTTPRequest(method, route, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery records a database query; outcome is "success" or "error".
+func ObserveDBQuery(operation, table, outcome string, duration time.Duration) {
+	dbQueryDuration.WithLabelValues(operation, table, outcome).Observe(duration.Seconds())
+}
+
+// ObserveBookCall records a call to the Book service.
+func ObserveBookCall(rpc, code string, duration time.Duration) {
+	bookClientRequests.WithLabelValues(rpc, code).Inc()
+	bookClientRequestDuration.WithLabelValues(rpc).Observe(duration.Seconds())
+}
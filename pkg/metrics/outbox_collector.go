@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OutboxCounter counts the outbox events that were not delivered yet.
+type OutboxCounter interface {
+	CountPendingEvents(ctx context.Context) (count int64, oldest time.Time, err error)
+}
+
+var (
+	outboxPendingDesc = prometheus.NewDesc(
+		"outbox_pending_events",
+		"Number of outbox events not yet delivered to the Book service.",
+		nil, nil,
+	)
+	outboxOldestDesc = prometheus.NewDesc(
+		"outbox_oldest_pending_event_age_seconds",
+		"Age of the oldest undelivered outbox event, 0 if there is none.",
+		nil, nil,
+	)
+)
+
+// outboxCollector reads the outbox backlog at scrape time. A growing age of
+// the oldest event means deliveries are stuck and should raise an alert.
+type outboxCollector struct {
+	counter OutboxCounter
+}
+
+// NewOutboxCollector reports the outbox backlog as the
+// "outbox_pending_events" and "outbox_oldest_pending_event_age_seconds"
+// gauges.
+func NewOutboxCollector(counter OutboxCounter) prometheus.Collector {
+	return &outboxCollector{counter: counter}
+}
+
+func (c *outboxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- outboxPendingDesc
+	ch <- outboxOldestDesc
+}
+
+func (c *outboxCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), categoryCountTimeout)
+	defer cancel()
+
+	count, oldest, err := c.counter.CountPendingEvents(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(outboxPendingDesc, err)
+		ch <- prometheus.NewInvalidMetric(outboxOldestDesc, err)
+		return
+	}
+
+	var age float64
+	if !oldest.IsZero() {
+		age = time.Since(oldest).Seconds()
+	}
+	ch <- prometheus.MustNewConstMetric(outboxPendingDesc, prometheus.GaugeValue, float64(count))
+	ch <- prometheus.MustNewConstMetric(outboxOldestDesc, prometheus.GaugeValue, age)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/shared/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	claimsContextKey      = "claims"
+	permissionsContextKey = "permissions"
+)
+
+// AuthorizationMiddleware resolves the permissions of the token validated by
+// JWTAuthMiddleware, which must run first, for RequirePermission and
+// HasPermission.
+func AuthorizationMiddleware(rolePermissions domain.RolePermissions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions := domain.PermissionSet{}
+		if claims, ok := c.Get(claimsContextKey); ok {
+			permissions = rolePermissions.Grant(claims.(*domain.TokenClaims))
+		}
+
+		c.Set(permissionsContextKey, permissions)
+		c.Next()
+	}
+}
+
+// RequirePermission rejects requests whose token lacks permission with 403.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			DenyPermission(c, permission)
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the token of the request grants permission.
+func HasPermission(c *gin.Context, permission string) bool {
+	permissions, _ := c.Value(permissionsContextKey).(domain.PermissionSet)
+	return permissions.Has(permission)
+}
+
+// DenyPermission aborts the request with 403, naming the missing permission.
+func DenyPermission(c *gin.Context, permission string) {
+	response.ErrorWithData(c, http.StatusForbidden, "MISSING_PERMISSION", "Missing permission "+permission, gin.H{"missingPermission": permission})
+	c.Abort()
+}
@@ -12,8 +12,10 @@ func BasicAuthMiddleware(cfg config.ConfigProvider) gin.HandlerFunc {
 	password := cfg.GetBasicAuthPassword()
 
 	return func(c *gin.Context) {
+		// Without configured credentials every request is rejected, so an
+		// empty BASIC_AUTH_USER never opens the protected routes.
 		user, pass, ok := c.Request.BasicAuth()
-		if !ok || user != username || pass != password {
+		if !ok || username == "" || user != username || pass != password {
 			c.Header("WWW-Authenticate", `Basic realm="Restricted"`)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 			c.Abort()
@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	sharedDomain "category-service/pkg/shared/domain"
+	"category-service/pkg/shared/response"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize bounds the request bodies read into memory to
+	// be hashed. A full batch of category writes fits well within it.
+	maxIdempotentBodySize = 1 << 20
+)
+
+// replayedHeaders are the response headers stored with an idempotent response
+// and sent again when it is replayed.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyStore persists idempotency keys; it is implemented by
+// repository.IdempotencyRepository.
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error)
+	GetIdempotencyKey(ctx context.Context, userID uint, key string) (*sharedDomain.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) (bool, error)
+	DeleteIdempotencyKey(ctx context.Context, key *sharedDomain.IdempotencyKey) error
+}
+
+// IdempotencyMiddleware makes writes carrying an Idempotency-Key header safe
+// to retry. The first request with a key is executed and its response stored
+// for ttl; repeated requests with the same key and payload get the stored
+// response instead of executing again. Keys are scoped to the user set by
+// JWTAuthMiddleware, which must run first. Server errors and panics are not
+// stored, so such requests can be retried with the same key. A key in
+// progress is held for lease only, so a request whose instance died does not
+// block its key until ttl.
+func IdempotencyMiddleware(store IdempotencyStore, ttl, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.ErrorWithCode(c, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxIdempotentBodySize))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request payload")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID := c.GetUint("userId")
+		now := time.Now()
+		owner := make([]byte, 16)
+		_, _ = rand.Read(owner)
+		record := &sharedDomain.IdempotencyKey{
+			UserID:          userID,
+			Key:             key,
+			Owner:           hex.EncodeToString(owner),
+			RequestHash:     requestHash(c.Request, body),
+			ResponseHeaders: "{}",
+			CreatedAt:       now,
+			ExpiresAt:       now.Add(lease),
+		}
+
+		ctx := c.Request.Context()
+		reserved, err := store.ReserveIdempotencyKey(ctx, record)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to process Idempotency-Key")
+			c.Abort()
+			return
+		}
+		if !reserved {
+			replayIdempotentResponse(c, store, record)
+			return
+		}
+
+		// The client may have gone away, but the outcome must still be stored.
+		storeCtx := context.WithoutCancel(ctx)
+
+		// Release the key unless the response was stored, including when the
+		// handler panics, so that the request can be retried right away.
+		stored := false
+		defer func() {
+			if !stored {
+				_ = store.DeleteIdempotencyKey(storeCtx, record)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		headers := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		encodedHeaders, _ := json.Marshal(headers)
+
+		record.StatusCode = recorder.Status()
+		record.ResponseBody = recorder.body.String()
+		record.ResponseHeaders = string(encodedHeaders)
+		record.ExpiresAt = time.Now().Add(ttl)
+		// If the lease expired and a retry took the key over, the response
+		// is not stored and the retry's record is left alone.
+		completed, err := store.CompleteIdempotencyKey(storeCtx, record)
+		stored = err == nil && completed
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already in use.
+func replayIdempotentResponse(c *gin.Context, store IdempotencyStore, record *sharedDomain.IdempotencyKey) {
+	defer c.Abort()
+
+	existing, err := store.GetIdempotencyKey(c.Request.Context(), record.UserID, record.Key)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to process Idempotency-Key")
+		return
+	}
+
+	switch {
+	case existing == nil || existing.StatusCode == 0:
+		response.ErrorWithCode(c, http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS", "A request with this Idempotency-Key is still being processed")
+	case existing.RequestHash != record.RequestHash:
+		response.ErrorWithCode(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different request")
+	default:
+		var headers map[string]string
+		_ = json.Unmarshal([]byte(existing.ResponseHeaders), &headers)
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+		c.Header(IdempotencyReplayedHeader, "true")
+		c.Status(existing.StatusCode)
+		_, _ = c.Writer.WriteString(existing.ResponseBody)
+	}
+}
+
+// requestHash identifies the payload of a request, so that a key reused for
+// a different request can be told apart from a retry.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
 	"category-service/pkg/token"
 	"fmt"
 	"net/http"
@@ -9,7 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func JWTAuthMiddleware(tokenService token.Token) gin.HandlerFunc {
+func JWTAuthMiddleware(tokenService token.Verifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
@@ -25,15 +27,22 @@ func JWTAuthMiddleware(tokenService token.Token) gin.HandlerFunc {
 			return
 		}
 
-		tokenClaims, err := tokenService.ValidateToken(parts[1])
+		tokenClaims, err := tokenService.ValidateToken(c.Request.Context(), parts[1])
 		if err != nil {
-			fmt.Println(err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			// Recorded on the context so the request logger reports why the
+			// token was rejected
+			reason := token.RejectionReason(err)
+			_ = c.Error(fmt.Errorf("token rejected (%s): %w", reason, err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": token.RejectionMessage(reason), "reason": reason})
 			c.Abort()
 			return
 		}
 
 		c.Set("userId", tokenClaims.UserID)
+		c.Set(claimsContextKey, tokenClaims)
+		ctx := domain.WithActor(c.Request.Context(), tokenClaims.UserID)
+		ctx = logger.AddFields(ctx, logger.Fields{"user_id": tokenClaims.UserID})
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID correlating the logs of a request. It is
+// taken from the client if present and always returned in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware assigns the request ID and puts a logger carrying it,
+// the route and the method into the request context. It must run before any
+// middleware that logs.
+func RequestIDMiddleware(base logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := domain.EnsureRequestID(c.GetHeader(RequestIDHeader))
+		c.Header(RequestIDHeader, requestID)
+		c.Set("requestId", requestID)
+
+		ctx := domain.WithRequestID(c.Request.Context(), requestID)
+		ctx = logger.WithContext(ctx, base.With(logger.Fields{
+			"request_id": requestID,
+			"route":      c.FullPath(),
+			"method":     c.Request.Method,
+		}))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AccessLogMiddleware logs every request once it completed, with the logger of
+// the request context so the entry includes its request and user ID. It
+// replaces the plain-text access log of gin.Logger.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		statusCode := c.Writer.Status()
+		fields := logger.Fields{
+			"path":        c.Request.URL.Path,
+			"status":      statusCode,
+			"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip":   c.ClientIP(),
+			"bytes":       c.Writer.Size(),
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+
+		log := logger.FromContext(c.Request.Context()).With(fields)
+		message := fmt.Sprintf("%s %s %d", c.Request.Method, c.Request.URL.Path, statusCode)
+		switch {
+		case statusCode >= http.StatusInternalServerError:
+			log.Error(message, "http_request", c.FullPath())
+		case statusCode >= http.StatusBadRequest:
+			log.Warn(message, "http_request", c.FullPath())
+		default:
+			log.Info(message, "http_request", c.FullPath())
+		}
+	}
+}
@@ -6,9 +6,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// Category names are unique among live categories only, so the name of a
+// soft-deleted category can be reused.
 type Category struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"unique;not null" json:"name"`
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	Name      string `gorm:"not null;uniqueIndex:idx_categories_name_live,where:deleted_at IS NULL" json:"name"`
+	Slug      string `gorm:"size:255;uniqueIndex" json:"slug"`
+	ParentID  *uint  `gorm:"index" json:"parentId"`
+	CreatedBy *uint  `json:"createdBy"`
+	UpdatedBy *uint  `json:"updatedBy"`
+	// Version is incremented on every change and guards against lost updates.
+	Version   uint           `gorm:"not null;default:1" json:"version"`
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	CategoryOperationCreate  = "create"
+	CategoryOperationUpdate  = "update"
+	CategoryOperationDelete  = "delete"
+	CategoryOperationRestore = "restore"
+	CategoryOperationPurge   = "purge"
+)
+
+// CategoryHistory is one entry of the append-only change log of a category.
+// Entries are kept when the category itself is purged.
+type CategoryHistory struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	CategoryID uint              `gorm:"not null;index:idx_category_history_category" json:"categoryId"`
+	Operation  string            `gorm:"size:20;not null" json:"operation"`
+	ActorID    *uint             `json:"actorId"`
+	Before     *CategorySnapshot `gorm:"type:jsonb" json:"before"`
+	After      *CategorySnapshot `gorm:"type:jsonb" json:"after"`
+	CreatedAt  time.Time         `gorm:"index:idx_category_history_category" json:"createdAt"`
+}
+
+func (CategoryHistory) TableName() string {
+	return "category_history"
+}
+
+// CategorySnapshot is the state of a category recorded in its history.
+type CategorySnapshot struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ParentID *uint  `json:"parentId"`
+}
+
+// NewCategorySnapshot captures the current state of category.
+func NewCategorySnapshot(category *Category) *CategorySnapshot {
+	return &CategorySnapshot{Name: category.Name, Slug: category.Slug, ParentID: category.ParentID}
+}
+
+func (s CategorySnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *CategorySnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return errors.New("unsupported category snapshot value")
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// CategorySlugRedirect keeps a former slug of a category resolvable after the
+// category was renamed.
+type CategorySlugRedirect struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Slug       string    `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	CategoryID uint      `gorm:"not null;index" json:"categoryId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
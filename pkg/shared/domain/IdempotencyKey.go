@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// IdempotencyKey records a write request made with an Idempotency-Key header
+// and, once it completed, its response, so that retries can be answered
+// without repeating the write. A StatusCode of 0 means the request is still
+// in progress; ExpiresAt is then the end of its lease, after which a retry
+// may take the key over, for example after the instance handling it died.
+// Owner identifies the request holding the key, so that a request whose key
+// was taken over can no longer complete or release it.
+type IdempotencyKey struct {
+	UserID          uint      `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	Key             string    `gorm:"primaryKey;size:255" json:"key"`
+	Owner           string    `gorm:"size:32;not null;default:''" json:"-"`
+	RequestHash     string    `gorm:"size:64;not null" json:"requestHash"`
+	StatusCode      int       `gorm:"not null;default:0" json:"statusCode"`
+	ResponseBody    string    `gorm:"type:text" json:"responseBody"`
+	ResponseHeaders string    `gorm:"type:jsonb" json:"responseHeaders"`
+	CreatedAt       time.Time `json:"createdAt"`
+	ExpiresAt       time.Time `gorm:"not null;index" json:"expiresAt"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OutboxAggregateCategory = "category"
+
+	OutboxEventCategorySaved   = "category.saved"
+	OutboxEventCategoryDeleted = "category.deleted"
+
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+)
+
+// OutboxEvent is a change that still has to be delivered to another service.
+// It is written in the same transaction as the change itself and picked up by
+// the outbox dispatcher, which guarantees at-least-once delivery.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	AggregateType string     `gorm:"size:50;not null;index:idx_outbox_aggregate" json:"aggregateType"`
+	AggregateID   uint       `gorm:"not null;index:idx_outbox_aggregate" json:"aggregateId"`
+	EventType     string     `gorm:"size:50;not null" json:"eventType"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"size:20;not null;default:pending;index:idx_outbox_pending" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_pending" json:"nextAttemptAt"`
+	LockedUntil   *time.Time `json:"lockedUntil,omitempty"`
+	LastError     string     `gorm:"type:text" json:"lastError,omitempty"`
+	// TraceParent is the W3C traceparent of the change, so that the
+	// delivery can be linked to the request that made it.
+	TraceParent string     `gorm:"size:64" json:"traceParent,omitempty"`
+	ProcessedAt *time.Time `json:"processedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// CategoryEventPayload is the payload of category outbox events.
+type CategoryEventPayload struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name,omitempty"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// NewCategorySavedEvent returns a pending event that delivers the current
+// state of category. traceParent links the delivery to the change.
+func NewCategorySavedEvent(category *Category, traceParent string) (*OutboxEvent, error) {
+	payload := CategoryEventPayload{ID: category.ID, Name: category.Name, Slug: category.Slug}
+	return newCategoryEvent(OutboxEventCategorySaved, payload, traceParent)
+}
+
+// NewCategoryDeletedEvent returns a pending event that delivers the deletion
+// of category id.
+func NewCategoryDeletedEvent(id uint, traceParent string) (*OutboxEvent, error) {
+	return newCategoryEvent(OutboxEventCategoryDeleted, CategoryEventPayload{ID: id}, traceParent)
+}
+
+func newCategoryEvent(eventType string, payload CategoryEventPayload, traceParent string) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		AggregateType: OutboxAggregateCategory,
+		AggregateID:   payload.ID,
+		EventType:     eventType,
+		Payload:       string(data),
+		Status:        OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+		TraceParent:   traceParent,
+	}, nil
+}
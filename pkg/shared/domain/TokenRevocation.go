@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RevokedToken revokes a single token by its "jti" claim. ExpiresAt is the
+// expiry of the token, after which the entry is no longer needed; nil keeps
+// the entry until it is deleted.
+type RevokedToken struct {
+	JTI       string     `gorm:"primaryKey;size:255" json:"jti"`
+	Reason    string     `gorm:"size:255" json:"reason"`
+	ExpiresAt *time.Time `gorm:"index" json:"expiresAt"`
+	RevokedAt time.Time  `json:"revokedAt"`
+}
+
+// UserTokenRevocation revokes every token of a user issued before
+// RevokedBefore, e.g. after a logout from all devices or a compromise.
+type UserTokenRevocation struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	RevokedBefore time.Time `gorm:"not null" json:"revokedBefore"`
+	Reason        string    `gorm:"size:255" json:"reason"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
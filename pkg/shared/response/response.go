@@ -1,9 +1,17 @@
 package response
 
-import "github.com/gin-gonic/gin"
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
 
 type Response struct {
 	Status  string      `json:"status"`
+	Code    string      `json:"code,omitempty"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
@@ -13,6 +21,32 @@ type Pagination struct {
 	PageSize    int `json:"pageSize"`
 	TotalPages  int `json:"totalPages"`
 	TotalItems  int `json:"totalItems"`
+
+	// In cursor mode only PageSize and the cursors are filled in.
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// statusCodes maps domain error kinds to HTTP status codes.
+var statusCodes = map[domain.ErrorKind]int{
+	domain.KindNotFound:            http.StatusNotFound,
+	domain.KindConflict:            http.StatusConflict,
+	domain.KindValidation:          http.StatusBadRequest,
+	domain.KindUpstreamUnavailable: http.StatusServiceUnavailable,
+	domain.KindPreconditionFailed:  http.StatusPreconditionFailed,
+}
+
+// defaultCodes are the error codes used when a handler reports a status
+// without a more specific domain error.
+var defaultCodes = map[int]string{
+	http.StatusBadRequest:          "BAD_REQUEST",
+	http.StatusUnauthorized:        "UNAUTHORIZED",
+	http.StatusForbidden:           "FORBIDDEN",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusConflict:            "CONFLICT",
+	http.StatusPreconditionFailed:  "PRECONDITION_FAILED",
+	http.StatusInternalServerError: "INTERNAL_ERROR",
+	http.StatusServiceUnavailable:  "SERVICE_UNAVAILABLE",
 }
 
 func Success(c *gin.Context, statusCode int, message string, data interface{}) {
@@ -33,8 +67,36 @@ func SuccessWithPagination(c *gin.Context, statusCode int, message string, data
 }
 
 func Error(c *gin.Context, statusCode int, message string) {
+	ErrorWithCode(c, statusCode, defaultCodes[statusCode], message)
+}
+
+func ErrorWithCode(c *gin.Context, statusCode int, code, message string) {
+	ErrorWithData(c, statusCode, code, message, nil)
+}
+
+// ErrorWithData reports an error together with details, such as the per-item
+// results of a bulk request.
+func ErrorWithData(c *gin.Context, statusCode int, code, message string, data interface{}) {
 	c.JSON(statusCode, Response{
 		Status:  "error",
+		Code:    code,
 		Message: message,
+		Data:    data,
 	})
 }
+
+// FromError writes the status code and error code of a domain error. Any
+// other error is reported as 500 with fallbackMessage, so internal details
+// never reach the client.
+func FromError(c *gin.Context, err error, fallbackMessage string) {
+	if domainErr, ok := domain.AsError(err); ok {
+		if statusCode, ok := statusCodes[domainErr.Kind]; ok {
+			ErrorWithCode(c, statusCode, domainErr.Code, domainErr.Message)
+			return
+		}
+	}
+
+	_ = c.Error(err)
+	logger.FromContext(c.Request.Context()).Error(fmt.Sprintf("%s: %v", fallbackMessage, err), "http_error", c.FullPath())
+	Error(c, http.StatusInternalServerError, fallbackMessage)
+}
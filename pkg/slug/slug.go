@@ -0,0 +1,82 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a slug produced by Make, leaving room
+// for a numeric suffix within the 255 characters stored in the database.
+const MaxLength = 100
+
+// Fallback is used when nothing of the input survives transliteration.
+const Fallback = "category"
+
+// transliterations covers letters that do not decompose into an ASCII base
+// letter plus combining marks.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th", 'ł': "l", 'Ł': "l",
+	'ı': "i",
+
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// Make converts name into a lowercase, hyphen separated ASCII slug.
+// Accented letters lose their accents and Cyrillic and Greek letters are
+// transliterated; any other character acts as a separator.
+func Make(name string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(s)
+	}
+
+	name = strings.ReplaceAll(name, "&", " and ")
+	for _, r := range norm.NFKD.String(name) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		lower := unicode.ToLower(r)
+		if lower < unicode.MaxASCII && (unicode.IsLetter(lower) || unicode.IsDigit(lower)) {
+			write(string(lower))
+		} else if t, ok := transliterations[lower]; ok {
+			write(t)
+		} else {
+			pendingHyphen = true
+		}
+	}
+
+	s := b.String()
+	if len(s) > MaxLength {
+		s = strings.TrimRight(s[:MaxLength], "-")
+		if i := strings.LastIndexByte(s, '-'); i > MaxLength/2 {
+			s = s[:i]
+		}
+	}
+
+	if s == "" {
+		return Fallback
+	}
+	return s
+}
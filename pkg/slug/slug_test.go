@@ -0,0 +1,77 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "simple", in: "Books", want: "books"},
+		{name: "spaces", in: "Science Fiction", want: "science-fiction"},
+		{name: "surrounding separators", in: "  --Science   Fiction!--  ", want: "science-fiction"},
+		{name: "digits", in: "Top 10 Novels", want: "top-10-novels"},
+		{name: "ampersand", in: "Arts & Crafts", want: "arts-and-crafts"},
+		{name: "ampersand without spaces", in: "R&D", want: "r-and-d"},
+		{name: "accents", in: "Crème Brûlée", want: "creme-brulee"},
+		{name: "ligatures and special letters", in: "Straße Ærø Łódź", want: "strasse-aero-lodz"},
+		{name: "cyrillic", in: "Художественная литература", want: "khudozhestvennaya-literatura"},
+		{name: "greek", in: "Φιλοσοφία", want: "filosofia"},
+		{name: "compatibility characters", in: "ｆｕｌｌ ｗｉｄｔｈ", want: "full-width"},
+		{name: "untransliterated script", in: "小说", want: Fallback},
+		{name: "mixed scripts", in: "Manga 漫画 Comics", want: "manga-comics"},
+		{name: "only punctuation", in: "!?!", want: Fallback},
+		{name: "empty", in: "", want: Fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeTruncates(t *testing.T) {
+	word := strings.Repeat("a", 30)
+	long := strings.Repeat("b", MaxLength+20)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "at a word boundary",
+			in:   strings.Repeat(word+" ", 5),
+			want: strings.TrimSuffix(strings.Repeat(word+"-", 3), "-"),
+		},
+		{
+			name: "inside a single long word",
+			in:   long,
+			want: long[:MaxLength],
+		},
+		{
+			name: "without a trailing hyphen",
+			in:   strings.Repeat("c", MaxLength-1) + " " + word,
+			want: strings.Repeat("c", MaxLength-1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Make(tt.in)
+			if got != tt.want {
+				t.Errorf("Make() = %q, want %q", got, tt.want)
+			}
+			if len(got) > MaxLength {
+				t.Errorf("Make() returned %d characters, want at most %d", len(got), MaxLength)
+			}
+		})
+	}
+}
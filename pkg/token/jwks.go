@@ -0,0 +1,257 @@
+package token
+
+import (
+	"category-service/pkg/logger"
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksMinRefreshInterval limits refreshes triggered by unknown key IDs.
+	jwksMinRefreshInterval = time.Minute
+	jwksFetchTimeout       = 10 * time.Second
+	maxJWKSSize            = 1 << 20
+)
+
+// JWKS is a key set loaded from a JSON Web Key Set document, either a local
+// file or an http(s) URL. All keys in the document are valid at the same
+// time, so the auth service can publish a new key before signing with it and
+// drop the old one after its tokens expired.
+type JWKS struct {
+	source string
+	client *http.Client
+	logger logger.Logger
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+	// lastAttempt is when the keys were last loaded or an unknown key ID
+	// last triggered a refresh, whether or not that refresh succeeded.
+	lastAttempt time.Time
+	// pending is closed when the refresh triggered by an unknown key ID
+	// finishes; nil when none is in progress. Concurrent misses wait for it
+	// instead of fetching the key set again.
+	pending chan struct{}
+}
+
+// NewJWKS loads the key set from source.
+func NewJWKS(ctx context.Context, source string, logger logger.Logger) (*JWKS, error) {
+	jwks := &JWKS{
+		source: source,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		logger: logger,
+	}
+	if err := jwks.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return jwks, nil
+}
+
+// Run refreshes the key set every interval until ctx is cancelled. A failed
+// refresh keeps the previous keys. A non-positive interval disables periodic
+// refreshes.
+func (j *JWKS) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.Refresh(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error(fmt.Sprintf("Failed to refresh JWKS: %v", err), "jwks", "refresh")
+		}
+	}
+}
+
+// Refresh reloads the key set from its source.
+func (j *JWKS) Refresh(ctx context.Context) error {
+	data, err := j.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.lastAttempt = time.Now()
+	j.mu.Unlock()
+
+	return nil
+}
+
+func (j *JWKS) VerificationKeys(ctx context.Context, kid string) ([]*rsa.PublicKey, error) {
+	if kid == "" {
+		return j.allKeys(), nil
+	}
+
+	if key, ok := j.key(kid); ok {
+		return []*rsa.PublicKey{key}, nil
+	}
+
+	// The key may have been published after the last refresh.
+	j.refreshForUnknownKey(ctx)
+	if key, ok := j.key(kid); ok {
+		return []*rsa.PublicKey{key}, nil
+	}
+
+	return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+// refreshForUnknownKey refreshes the key set at most once per
+// jwksMinRefreshInterval. The attempt is recorded before fetching so that an
+// unavailable source does not make every unknown key ID wait for a fetch, and
+// callers arriving while a refresh is in progress wait for that one.
+func (j *JWKS) refreshForUnknownKey(ctx context.Context) {
+	j.mu.Lock()
+	refresh := j.pending
+	if refresh == nil {
+		if time.Since(j.lastAttempt) < jwksMinRefreshInterval {
+			j.mu.Unlock()
+			return
+		}
+		j.lastAttempt = time.Now()
+		refresh = make(chan struct{})
+		j.pending = refresh
+		j.mu.Unlock()
+
+		fetchCtx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+		err := j.Refresh(fetchCtx)
+		cancel()
+		if err != nil {
+			j.logger.Error(fmt.Sprintf("Failed to refresh JWKS: %v", err), "jwks", "refresh")
+		}
+
+		j.mu.Lock()
+		j.pending = nil
+		j.mu.Unlock()
+		close(refresh)
+		return
+	}
+	j.mu.Unlock()
+
+	select {
+	case <-refresh:
+	case <-ctx.Done():
+	}
+}
+
+func (j *JWKS) key(kid string) (*rsa.PublicKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func (j *JWKS) allKeys() []*rsa.PublicKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	keys := make([]*rsa.PublicKey, 0, len(j.keys))
+	for _, key := range j.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (j *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(j.source, "http://") && !strings.HasPrefix(j.source, "https://") {
+		return os.ReadFile(j.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching JWKS: unexpected status %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS returns the RSA signing keys of a JWKS document by key ID. Keys of
+// other types or uses are skipped.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parsing JWKS key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no RSA signing keys")
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid key parameters")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,179 @@
+package token
+
+import (
+	"category-service/pkg/logger"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(message, event, key string)           {}
+func (nopLogger) Warn(message, event, key string)           {}
+func (nopLogger) Error(message, event, key string)          {}
+func (nopLogger) Debug(message, event, key string)          {}
+func (nopLogger) Fatal(message, event, key string)          {}
+func (nopLogger) Panic(message, event, key string)          {}
+func (nopLogger) SetOutput(output *os.File)                 {}
+func (nopLogger) SetFormat(format string)                   {}
+func (l nopLogger) With(fields logger.Fields) logger.Logger { return l }
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return key
+}
+
+func jwkJSON(kid string, key *rsa.PublicKey, extra string) string {
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	return fmt.Sprintf(`{"kty":"RSA","kid":%q,"n":%q,"e":%q%s}`, kid, n, e, extra)
+}
+
+func TestParseJWKS(t *testing.T) {
+	key := &generateKey(t).PublicKey
+
+	tests := []struct {
+		name     string
+		document string
+		wantKids []string
+		wantErr  bool
+	}{
+		{name: "single key", document: `{"keys":[` + jwkJSON("a", key, "") + `]}`, wantKids: []string{"a"}},
+		{name: "signing use", document: `{"keys":[` + jwkJSON("a", key, `,"use":"sig"`) + `]}`, wantKids: []string{"a"}},
+		{
+			name:     "other types and uses are skipped",
+			document: `{"keys":[` + jwkJSON("enc", key, `,"use":"enc"`) + `,{"kty":"EC","kid":"ec"},` + jwkJSON("a", key, "") + `]}`,
+			wantKids: []string{"a"},
+		},
+		{name: "padded values", document: `{"keys":[` + strings.Replace(jwkJSON("a", key, ""), `","e"`, `==","e"`, 1) + `]}`, wantKids: []string{"a"}},
+		{name: "no signing keys", document: `{"keys":[` + jwkJSON("enc", key, `,"use":"enc"`) + `]}`, wantErr: true},
+		{name: "no keys", document: `{"keys":[]}`, wantErr: true},
+		{name: "not json", document: `keys`, wantErr: true},
+		{name: "bad modulus", document: `{"keys":[{"kty":"RSA","kid":"a","n":"!","e":"AQAB"}]}`, wantErr: true},
+		{name: "empty modulus", document: `{"keys":[{"kty":"RSA","kid":"a","n":"","e":"AQAB"}]}`, wantErr: true},
+		{name: "small exponent", document: `{"keys":[{"kty":"RSA","kid":"a","n":"AQAB","e":"AQ"}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS([]byte(tt.document))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseJWKS() = %v, want an error", keys)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseJWKS() error = %v", err)
+			}
+			if len(keys) != len(tt.wantKids) {
+				t.Fatalf("parseJWKS() returned %d keys, want %d", len(keys), len(tt.wantKids))
+			}
+			for _, kid := range tt.wantKids {
+				if got, ok := keys[kid]; !ok || !got.Equal(key) {
+					t.Errorf("parseJWKS() key %q = %v, want the published key", kid, got)
+				}
+			}
+		})
+	}
+}
+
+func TestJWKSRefreshForUnknownKey(t *testing.T) {
+	current := &generateKey(t).PublicKey
+	next := &generateKey(t).PublicKey
+
+	tests := []struct {
+		name string
+		// respond serves the fetches after the one made by NewJWKS.
+		respond    func(w http.ResponseWriter)
+		sinceFetch time.Duration
+		wantKey    bool
+		wantFetch  int32
+	}{
+		{
+			name: "new key is fetched",
+			respond: func(w http.ResponseWriter) {
+				fmt.Fprintf(w, `{"keys":[%s,%s]}`, jwkJSON("current", current, ""), jwkJSON("next", next, ""))
+			},
+			sinceFetch: jwksMinRefreshInterval,
+			wantKey:    true,
+			wantFetch:  2,
+		},
+		{
+			name: "refresh is rate limited",
+			respond: func(w http.ResponseWriter) {
+				fmt.Fprintf(w, `{"keys":[%s,%s]}`, jwkJSON("current", current, ""), jwkJSON("next", next, ""))
+			},
+			sinceFetch: jwksMinRefreshInterval / 2,
+			wantFetch:  1,
+		},
+		{
+			name: "failing source is fetched once",
+			respond: func(w http.ResponseWriter) {
+				time.Sleep(50 * time.Millisecond)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			sinceFetch: jwksMinRefreshInterval,
+			wantFetch:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if fetches.Add(1) == 1 {
+					fmt.Fprintf(w, `{"keys":[%s]}`, jwkJSON("current", current, ""))
+					return
+				}
+				tt.respond(w)
+			}))
+			defer server.Close()
+
+			jwks, err := NewJWKS(context.Background(), server.URL, nopLogger{})
+			if err != nil {
+				t.Fatalf("NewJWKS() error = %v", err)
+			}
+			jwks.lastAttempt = time.Now().Add(-tt.sinceFetch)
+
+			// Concurrent misses share one refresh, and a failed refresh
+			// counts as an attempt.
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					keys, err := jwks.VerificationKeys(context.Background(), "next")
+					if gotKey := err == nil && len(keys) == 1 && keys[0].Equal(next); gotKey != tt.wantKey {
+						t.Errorf("VerificationKeys() = %v, %v; want key found %v", keys, err, tt.wantKey)
+					}
+				}()
+			}
+			wg.Wait()
+			if _, err := jwks.VerificationKeys(context.Background(), "unknown"); err == nil {
+				t.Error("VerificationKeys() of an unknown key succeeded")
+			}
+
+			if got := fetches.Load(); got != tt.wantFetch {
+				t.Errorf("fetched the key set %d times, want %d", got, tt.wantFetch)
+			}
+		})
+	}
+}
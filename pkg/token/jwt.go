@@ -2,63 +2,101 @@ package token
 
 import (
 	"category-service/internal/domain"
-	"crypto/rsa"
+	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-type JWT struct {
-	publicKey  *rsa.PublicKey
-	privateKey *rsa.PrivateKey
+// VerifierOptions are the claims and algorithms a token must satisfy besides
+// a valid signature.
+type VerifierOptions struct {
+	// Issuers lists the accepted "iss" values; empty accepts any issuer.
+	Issuers []string
+	// Audiences lists the accepted "aud" values, of which the token must
+	// contain at least one; empty accepts any audience.
+	Audiences []string
+	// Algorithms lists the accepted RSA signing algorithms, RS256 by default.
+	Algorithms []string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat.
+	Leeway time.Duration
 }
 
-func NewJWT(publicKey *rsa.PublicKey, privateKey *rsa.PrivateKey) Token {
-	return &JWT{
-		publicKey:  publicKey,
-		privateKey: privateKey,
-	}
+type JWTVerifier struct {
+	keys   KeyProvider
+	opts   VerifierOptions
+	parser *jwt.Parser
 }
 
-func (j *JWT) GenerateToken(userId uint, expired time.Duration) (string, error) {
-	claims := domain.TokenClaims{
-		UserID: userId,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expired)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "go-jwt-auth-service",
-			Subject:   "auth_token",
-		},
+// NewVerifier verifies tokens against the keys of keys, such as a single PEM
+// public key or a JWKS.
+func NewVerifier(keys KeyProvider, opts VerifierOptions) Verifier {
+	if len(opts.Algorithms) == 0 {
+		opts.Algorithms = []string{jwt.SigningMethodRS256.Alg()}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	return &JWTVerifier{
+		keys: keys,
+		opts: opts,
+		parser: jwt.NewParser(
+			jwt.WithLeeway(opts.Leeway),
+			jwt.WithExpirationRequired(),
+		),
+	}
+}
 
-	signedToken, err := token.SignedString(j.privateKey)
+// ValidateToken verifies tokenString and its claims. Errors can be classified
+// with RejectionReason.
+func (j *JWTVerifier) ValidateToken(ctx context.Context, tokenString string) (*domain.TokenClaims, error) {
+	token, err := j.parser.ParseWithClaims(tokenString, &domain.TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return j.verificationKey(ctx, token)
+	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*domain.TokenClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return signedToken, nil
+	if len(j.opts.Issuers) > 0 && !slices.Contains(j.opts.Issuers, claims.Issuer) {
+		return nil, fmt.Errorf("%w: %q", jwt.ErrTokenInvalidIssuer, claims.Issuer)
+	}
+	if len(j.opts.Audiences) > 0 && !slices.ContainsFunc(claims.Audience, func(audience string) bool {
+		return slices.Contains(j.opts.Audiences, audience)
+	}) {
+		return nil, fmt.Errorf("%w: %q", jwt.ErrTokenInvalidAudience, claims.Audience)
+	}
+
+	return claims, nil
 }
 
-func (j *JWT) ValidateToken(tokenString string) (*domain.TokenClaims, error) {
-	// Parse token dan verifikasi menggunakan publicKey
-	token, err := jwt.ParseWithClaims(tokenString, &domain.TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Pastikan metode signing yang digunakan adalah RS256
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+func (j *JWTVerifier) verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	// Only RSA algorithms are accepted, as the keys are RSA keys
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		if _, ok := token.Method.(*jwt.SigningMethodRSAPSS); !ok {
+			return nil, fmt.Errorf("%w: %v", ErrAlgorithmNotAllowed, token.Header["alg"])
 		}
-		return j.publicKey, nil
-	})
+	}
+	if !slices.Contains(j.opts.Algorithms, token.Method.Alg()) {
+		return nil, fmt.Errorf("%w: %v", ErrAlgorithmNotAllowed, token.Header["alg"])
+	}
 
+	kid, _ := token.Header["kid"].(string)
+	keys, err := j.keys.VerificationKeys(ctx, kid)
 	if err != nil {
 		return nil, err
 	}
-	if claims, ok := token.Claims.(*domain.TokenClaims); ok && token.Valid {
-		fmt.Println(claims.UserID)
-		return claims, nil
+	if len(keys) == 1 {
+		return keys[0], nil
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	keySet := jwt.VerificationKeySet{}
+	for _, key := range keys {
+		keySet.Keys = append(keySet.Keys, key)
+	}
+	return keySet, nil
 }
@@ -0,0 +1,31 @@
+package token
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeyProvider resolves the public keys tokens are verified against.
+type KeyProvider interface {
+	// VerificationKeys returns the key identified by kid or, if kid is
+	// empty, every key that may have signed the token. ctx bounds any
+	// refresh of the keys needed to find kid.
+	VerificationKeys(ctx context.Context, kid string) ([]*rsa.PublicKey, error)
+}
+
+// staticKey verifies every token against a single key, such as one loaded
+// from a PEM file.
+type staticKey struct {
+	publicKey *rsa.PublicKey
+}
+
+func NewStaticKeyProvider(publicKey *rsa.PublicKey) KeyProvider {
+	return &staticKey{publicKey: publicKey}
+}
+
+func (k *staticKey) VerificationKeys(ctx context.Context, kid string) ([]*rsa.PublicKey, error) {
+	return []*rsa.PublicKey{k.publicKey}, nil
+}
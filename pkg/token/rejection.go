@@ -0,0 +1,75 @@
+package token
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Rejection reasons are stable identifiers of why a token was not accepted,
+// suitable for logs and API responses.
+const (
+	ReasonMalformed           = "malformed"
+	ReasonExpired             = "expired"
+	ReasonNotYetValid         = "not_yet_valid"
+	ReasonMissingClaim        = "missing_claim"
+	ReasonBadSignature        = "bad_signature"
+	ReasonUnknownKey          = "unknown_key"
+	ReasonAlgorithmNotAllowed = "algorithm_not_allowed"
+	ReasonWrongIssuer         = "wrong_issuer"
+	ReasonWrongAudience       = "wrong_audience"
+	ReasonRevoked             = "revoked"
+	ReasonInvalid             = "invalid"
+)
+
+var ErrAlgorithmNotAllowed = errors.New("signing algorithm is not allowed")
+
+var rejectionMessages = map[string]string{
+	ReasonMalformed:           "Token is malformed",
+	ReasonExpired:             "Token has expired",
+	ReasonNotYetValid:         "Token is not valid yet",
+	ReasonMissingClaim:        "Token is missing a required claim",
+	ReasonBadSignature:        "Token signature is invalid",
+	ReasonUnknownKey:          "Token was signed with an unknown key",
+	ReasonAlgorithmNotAllowed: "Token signing algorithm is not allowed",
+	ReasonWrongIssuer:         "Token was issued by an untrusted issuer",
+	ReasonWrongAudience:       "Token is not intended for this service",
+	ReasonRevoked:             "Token has been revoked",
+	ReasonInvalid:             "Invalid token",
+}
+
+// RejectionReason classifies an error returned by Verifier.ValidateToken.
+func RejectionReason(err error) string {
+	switch {
+	case errors.Is(err, ErrTokenRevoked):
+		return ReasonRevoked
+	case errors.Is(err, ErrAlgorithmNotAllowed):
+		return ReasonAlgorithmNotAllowed
+	case errors.Is(err, ErrUnknownKey):
+		return ReasonUnknownKey
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ReasonMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return ReasonBadSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ReasonExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return ReasonNotYetValid
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return ReasonMissingClaim
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ReasonWrongIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ReasonWrongAudience
+	default:
+		return ReasonInvalid
+	}
+}
+
+// RejectionMessage returns a human-readable message for a rejection reason.
+func RejectionMessage(reason string) string {
+	if message, ok := rejectionMessages[reason]; ok {
+		return message
+	}
+	return rejectionMessages[ReasonInvalid]
+}
@@ -0,0 +1,192 @@
+package token
+
+import (
+	"category-service/internal/domain"
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keySet resolves a fixed set of keys by key ID.
+type keySet map[string]*rsa.PublicKey
+
+func (k keySet) VerificationKeys(ctx context.Context, kid string) ([]*rsa.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	return []*rsa.PublicKey{key}, nil
+}
+
+func TestRejectionReason(t *testing.T) {
+	key := generateKey(t)
+	otherKey := generateKey(t)
+	verifier := NewVerifier(keySet{"current": &key.PublicKey}, VerifierOptions{
+		Issuers:   []string{"auth-service"},
+		Audiences: []string{"category-service"},
+	})
+
+	now := time.Now()
+	validClaims := func() domain.TokenClaims {
+		return domain.TokenClaims{
+			UserID: 1,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "auth-service",
+				Audience:  jwt.ClaimStrings{"category-service"},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		}
+	}
+	sign := func(method jwt.SigningMethod, kid string, signingKey interface{}, claims domain.TokenClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatalf("signing token: %v", err)
+		}
+		return signed
+	}
+	signRS256 := func(claims domain.TokenClaims) string {
+		return sign(jwt.SigningMethodRS256, "current", key, claims)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{
+			name:  "malformed",
+			token: "not.a.token",
+			want:  ReasonMalformed,
+		},
+		{
+			name:  "bad signature",
+			token: sign(jwt.SigningMethodRS256, "current", otherKey, validClaims()),
+			want:  ReasonBadSignature,
+		},
+		{
+			name:  "unknown key",
+			token: sign(jwt.SigningMethodRS256, "retired", key, validClaims()),
+			want:  ReasonUnknownKey,
+		},
+		{
+			name:  "algorithm not allowed",
+			token: sign(jwt.SigningMethodRS512, "current", key, validClaims()),
+			want:  ReasonAlgorithmNotAllowed,
+		},
+		{
+			name:  "hmac algorithm",
+			token: sign(jwt.SigningMethodHS256, "current", []byte("secret"), validClaims()),
+			want:  ReasonAlgorithmNotAllowed,
+		},
+		{
+			name: "expired",
+			token: signRS256(func() domain.TokenClaims {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+				return claims
+			}()),
+			want: ReasonExpired,
+		},
+		{
+			name: "not valid yet",
+			token: signRS256(func() domain.TokenClaims {
+				claims := validClaims()
+				claims.NotBefore = jwt.NewNumericDate(now.Add(time.Hour))
+				return claims
+			}()),
+			want: ReasonNotYetValid,
+		},
+		{
+			name: "missing expiry",
+			token: signRS256(func() domain.TokenClaims {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return claims
+			}()),
+			want: ReasonMissingClaim,
+		},
+		{
+			name: "wrong issuer",
+			token: signRS256(func() domain.TokenClaims {
+				claims := validClaims()
+				claims.Issuer = "someone-else"
+				return claims
+			}()),
+			want: ReasonWrongIssuer,
+		},
+		{
+			name: "wrong audience",
+			token: signRS256(func() domain.TokenClaims {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"book-service"}
+				return claims
+			}()),
+			want: ReasonWrongAudience,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.ValidateToken(context.Background(), tt.token)
+			if err == nil {
+				t.Fatal("ValidateToken() succeeded, want an error")
+			}
+			if got := RejectionReason(err); got != tt.want {
+				t.Errorf("RejectionReason(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := verifier.ValidateToken(context.Background(), signRS256(validClaims())); err != nil {
+		t.Errorf("ValidateToken() of a valid token error = %v", err)
+	}
+}
+
+func TestRejectionReasonOfOtherErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "revoked", err: ErrTokenRevoked, want: ReasonRevoked},
+		{name: "wrapped revoked", err: fmt.Errorf("checking revocation: %w", ErrTokenRevoked), want: ReasonRevoked},
+		{name: "unknown", err: errors.New("invalid token"), want: ReasonInvalid},
+		{name: "nil", err: nil, want: ReasonInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RejectionReason(tt.err); got != tt.want {
+				t.Errorf("RejectionReason(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectionMessage(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   string
+	}{
+		{reason: ReasonExpired, want: "Token has expired"},
+		{reason: ReasonRevoked, want: "Token has been revoked"},
+		{reason: "no_such_reason", want: "Invalid token"},
+		{reason: "", want: "Invalid token"},
+	}
+
+	for _, tt := range tests {
+		if got := RejectionMessage(tt.reason); got != tt.want {
+			t.Errorf("RejectionMessage(%q) = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
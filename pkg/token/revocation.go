@@ -0,0 +1,242 @@
+package token
+
+import (
+	"category-service/internal/domain"
+	"category-service/pkg/logger"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationStore persists revocations; it is implemented by
+// repository.RevocationRepository.
+type RevocationStore interface {
+	SaveRevokedToken(ctx context.Context, token *sharedDomain.RevokedToken) error
+	SaveUserTokenRevocation(ctx context.Context, revocation *sharedDomain.UserTokenRevocation) error
+	GetRevokedTokens(ctx context.Context) ([]*sharedDomain.RevokedToken, error)
+	GetUserTokenRevocations(ctx context.Context) ([]*sharedDomain.UserTokenRevocation, error)
+	DeleteRevokedToken(ctx context.Context, jti string) (bool, error)
+	DeleteUserTokenRevocation(ctx context.Context, userID uint) (bool, error)
+	DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RevocationList answers revocation checks from memory. Changes made through
+// it apply immediately on this instance; changes made by other instances are
+// picked up by the periodic refresh.
+type RevocationList struct {
+	store  RevocationStore
+	logger logger.Logger
+
+	mu            sync.RWMutex
+	revokedTokens map[string]*sharedDomain.RevokedToken
+	revokedUsers  map[uint]time.Time
+	// refreshing counts the refreshes in progress. While there are any, local
+	// changes are also recorded in changes, so that a refresh that read the
+	// store before a change was saved reapplies it instead of losing it.
+	refreshing int
+	changes    []revocationChange
+}
+
+// revocationChange applies a change made through the list to its maps.
+type revocationChange func(tokens map[string]*sharedDomain.RevokedToken, users map[uint]time.Time)
+
+// NewRevocationList loads the current revocations from store.
+func NewRevocationList(ctx context.Context, store RevocationStore, logger logger.Logger) (*RevocationList, error) {
+	list := &RevocationList{store: store, logger: logger}
+	if err := list.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Run refreshes the list every interval until ctx is cancelled, and deletes
+// entries of tokens that have expired anyway. A non-positive interval
+// disables periodic refreshes.
+func (l *RevocationList) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := l.store.DeleteExpiredRevokedTokens(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			l.logger.Error(fmt.Sprintf("Failed to delete expired token revocations: %v", err), "revocation", "cleanup")
+		}
+		if err := l.Refresh(ctx); err != nil && ctx.Err() == nil {
+			l.logger.Error(fmt.Sprintf("Failed to refresh token revocations: %v", err), "revocation", "refresh")
+		}
+	}
+}
+
+// Refresh reloads all revocations from the store. Changes made through the
+// list while it reads the store are applied on top of what it read.
+func (l *RevocationList) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	l.refreshing++
+	start := len(l.changes)
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.refreshing--
+		if l.refreshing == 0 {
+			l.changes = nil
+		}
+		l.mu.Unlock()
+	}()
+
+	tokens, err := l.store.GetRevokedTokens(ctx)
+	if err != nil {
+		return err
+	}
+	users, err := l.store.GetUserTokenRevocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	revokedTokens := make(map[string]*sharedDomain.RevokedToken, len(tokens))
+	for _, token := range tokens {
+		revokedTokens[token.JTI] = token
+	}
+	revokedUsers := make(map[uint]time.Time, len(users))
+	for _, user := range users {
+		revokedUsers[user.UserID] = user.RevokedBefore
+	}
+
+	l.mu.Lock()
+	for _, change := range l.changes[start:] {
+		change(revokedTokens, revokedUsers)
+	}
+	l.revokedTokens = revokedTokens
+	l.revokedUsers = revokedUsers
+	l.mu.Unlock()
+
+	return nil
+}
+
+// apply makes a change that was saved to the store visible on this instance.
+func (l *RevocationList) apply(change revocationChange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change(l.revokedTokens, l.revokedUsers)
+	if l.refreshing > 0 {
+		l.changes = append(l.changes, change)
+	}
+}
+
+// IsRevoked reports whether the token with claims was revoked by its ID or
+// because all tokens of its user issued before a point in time were revoked.
+// Tokens without "iat" count as issued before any such point.
+func (l *RevocationList) IsRevoked(claims *domain.TokenClaims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if claims.ID != "" {
+		if _, ok := l.revokedTokens[claims.ID]; ok {
+			return true
+		}
+	}
+
+	revokedBefore, ok := l.revokedUsers[claims.UserID]
+	if !ok {
+		return false
+	}
+	return claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revokedBefore)
+}
+
+func (l *RevocationList) RevokeToken(ctx context.Context, token *sharedDomain.RevokedToken) error {
+	token.RevokedAt = time.Now()
+	if err := l.store.SaveRevokedToken(ctx, token); err != nil {
+		return err
+	}
+
+	l.apply(func(tokens map[string]*sharedDomain.RevokedToken, _ map[uint]time.Time) {
+		tokens[token.JTI] = token
+	})
+	return nil
+}
+
+func (l *RevocationList) RevokeUserTokens(ctx context.Context, revocation *sharedDomain.UserTokenRevocation) error {
+	if err := l.store.SaveUserTokenRevocation(ctx, revocation); err != nil {
+		return err
+	}
+
+	l.apply(func(_ map[string]*sharedDomain.RevokedToken, users map[uint]time.Time) {
+		users[revocation.UserID] = revocation.RevokedBefore
+	})
+	return nil
+}
+
+// UnrevokeToken deletes the revocation of a token and reports whether one
+// existed.
+func (l *RevocationList) UnrevokeToken(ctx context.Context, jti string) (bool, error) {
+	deleted, err := l.store.DeleteRevokedToken(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	l.apply(func(tokens map[string]*sharedDomain.RevokedToken, _ map[uint]time.Time) {
+		delete(tokens, jti)
+	})
+	return deleted, nil
+}
+
+// UnrevokeUserTokens deletes the revocation of a user's tokens and reports
+// whether one existed.
+func (l *RevocationList) UnrevokeUserTokens(ctx context.Context, userID uint) (bool, error) {
+	deleted, err := l.store.DeleteUserTokenRevocation(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	l.apply(func(_ map[string]*sharedDomain.RevokedToken, users map[uint]time.Time) {
+		delete(users, userID)
+	})
+	return deleted, nil
+}
+
+// RevokedTokens returns the revoked tokens that have not expired yet.
+func (l *RevocationList) RevokedTokens(ctx context.Context) ([]*sharedDomain.RevokedToken, error) {
+	return l.store.GetRevokedTokens(ctx)
+}
+
+func (l *RevocationList) UserTokenRevocations(ctx context.Context) ([]*sharedDomain.UserTokenRevocation, error) {
+	return l.store.GetUserTokenRevocations(ctx)
+}
+
+// revocationVerifier rejects revoked tokens after verifying them.
+type revocationVerifier struct {
+	verifier    Verifier
+	revocations *RevocationList
+}
+
+// NewRevocationVerifier wraps verifier so that tokens in revocations fail
+// validation with ErrTokenRevoked.
+func NewRevocationVerifier(verifier Verifier, revocations *RevocationList) Verifier {
+	return &revocationVerifier{verifier: verifier, revocations: revocations}
+}
+
+func (v *revocationVerifier) ValidateToken(ctx context.Context, tokenString string) (*domain.TokenClaims, error) {
+	claims, err := v.verifier.ValidateToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if v.revocations.IsRevoked(claims) {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
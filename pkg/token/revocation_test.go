@@ -0,0 +1,186 @@
+package token
+
+import (
+	"category-service/internal/domain"
+	sharedDomain "category-service/pkg/shared/domain"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// memoryRevocationStore keeps revocations in memory. While reads is set,
+// GetRevokedTokens signals on it and waits on release, so that tests can
+// change the list in the middle of a refresh.
+type memoryRevocationStore struct {
+	mu      sync.Mutex
+	tokens  map[string]*sharedDomain.RevokedToken
+	users   map[uint]time.Time
+	reads   chan struct{}
+	release chan struct{}
+}
+
+func newMemoryRevocationStore() *memoryRevocationStore {
+	return &memoryRevocationStore{tokens: map[string]*sharedDomain.RevokedToken{}, users: map[uint]time.Time{}}
+}
+
+func (s *memoryRevocationStore) SaveRevokedToken(ctx context.Context, token *sharedDomain.RevokedToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.JTI] = token
+	return nil
+}
+
+func (s *memoryRevocationStore) SaveUserTokenRevocation(ctx context.Context, revocation *sharedDomain.UserTokenRevocation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[revocation.UserID] = revocation.RevokedBefore
+	return nil
+}
+
+func (s *memoryRevocationStore) GetRevokedTokens(ctx context.Context) ([]*sharedDomain.RevokedToken, error) {
+	s.mu.Lock()
+	tokens := make([]*sharedDomain.RevokedToken, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, token)
+	}
+	s.mu.Unlock()
+
+	if s.reads != nil {
+		s.reads <- struct{}{}
+		<-s.release
+	}
+	return tokens, nil
+}
+
+func (s *memoryRevocationStore) GetUserTokenRevocations(ctx context.Context) ([]*sharedDomain.UserTokenRevocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]*sharedDomain.UserTokenRevocation, 0, len(s.users))
+	for userID, revokedBefore := range s.users {
+		users = append(users, &sharedDomain.UserTokenRevocation{UserID: userID, RevokedBefore: revokedBefore})
+	}
+	return users, nil
+}
+
+func (s *memoryRevocationStore) DeleteRevokedToken(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.tokens[jti]
+	delete(s.tokens, jti)
+	return ok, nil
+}
+
+func (s *memoryRevocationStore) DeleteUserTokenRevocation(ctx context.Context, userID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.users[userID]
+	delete(s.users, userID)
+	return ok, nil
+}
+
+func (s *memoryRevocationStore) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRevocationListChangesDuringRefresh(t *testing.T) {
+	ctx := context.Background()
+	issuedAt := time.Now().Add(-time.Hour)
+	claims := func(jti string, userID uint) *domain.TokenClaims {
+		return &domain.TokenClaims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ID: jti, IssuedAt: jwt.NewNumericDate(issuedAt)}}
+	}
+
+	tests := []struct {
+		name string
+		// before runs before the refresh, during while it reads the store.
+		before      func(l *RevocationList) error
+		during      func(l *RevocationList) error
+		claims      *domain.TokenClaims
+		wantRevoked bool
+	}{
+		{
+			name: "revoked token",
+			during: func(l *RevocationList) error {
+				return l.RevokeToken(ctx, &sharedDomain.RevokedToken{JTI: "jti-1"})
+			},
+			claims:      claims("jti-1", 1),
+			wantRevoked: true,
+		},
+		{
+			name: "revoked user",
+			during: func(l *RevocationList) error {
+				return l.RevokeUserTokens(ctx, &sharedDomain.UserTokenRevocation{UserID: 2, RevokedBefore: time.Now()})
+			},
+			claims:      claims("jti-2", 2),
+			wantRevoked: true,
+		},
+		{
+			name: "unrevoked token",
+			before: func(l *RevocationList) error {
+				return l.RevokeToken(ctx, &sharedDomain.RevokedToken{JTI: "jti-3"})
+			},
+			during: func(l *RevocationList) error {
+				_, err := l.UnrevokeToken(ctx, "jti-3")
+				return err
+			},
+			claims: claims("jti-3", 3),
+		},
+		{
+			name: "unrevoked user",
+			before: func(l *RevocationList) error {
+				return l.RevokeUserTokens(ctx, &sharedDomain.UserTokenRevocation{UserID: 4, RevokedBefore: time.Now()})
+			},
+			during: func(l *RevocationList) error {
+				_, err := l.UnrevokeUserTokens(ctx, 4)
+				return err
+			},
+			claims: claims("jti-4", 4),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryRevocationStore()
+			list, err := NewRevocationList(ctx, store, nopLogger{})
+			if err != nil {
+				t.Fatalf("NewRevocationList() error = %v", err)
+			}
+			if tt.before != nil {
+				if err := tt.before(list); err != nil {
+					t.Fatalf("change before refresh: %v", err)
+				}
+			}
+
+			store.reads = make(chan struct{})
+			store.release = make(chan struct{})
+			refreshed := make(chan error)
+			go func() { refreshed <- list.Refresh(ctx) }()
+
+			// The refresh has read the store; the change is not part of
+			// what it read.
+			<-store.reads
+			if err := tt.during(list); err != nil {
+				t.Fatalf("change during refresh: %v", err)
+			}
+			close(store.release)
+			if err := <-refreshed; err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+
+			if got := list.IsRevoked(tt.claims); got != tt.wantRevoked {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.wantRevoked)
+			}
+
+			// The next refresh reads the change from the store.
+			store.reads = nil
+			if err := list.Refresh(ctx); err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+			if got := list.IsRevoked(tt.claims); got != tt.wantRevoked {
+				t.Errorf("IsRevoked() after another refresh = %v, want %v", got, tt.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package token
+
+import (
+	"category-service/internal/domain"
+	"crypto/rsa"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTSigner struct {
+	privateKey *rsa.PrivateKey
+	keyID      string
+}
+
+// NewSigner signs tokens with privateKey. A non-empty keyID is set as the
+// "kid" header, so that verifiers using a JWKS can select the key.
+func NewSigner(privateKey *rsa.PrivateKey, keyID string) Signer {
+	return &JWTSigner{privateKey: privateKey, keyID: keyID}
+}
+
+// GenerateToken signs claims, setting their expiry to expired from now.
+func (s *JWTSigner) GenerateToken(claims domain.TokenClaims, expired time.Duration) (string, error) {
+	now := time.Now()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expired))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	if claims.Issuer == "" {
+		claims.Issuer = "go-jwt-auth-service"
+	}
+	if claims.Subject == "" {
+		claims.Subject = "auth_token"
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
+
+	signedToken, err := token.SignedString(s.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return signedToken, nil
+}
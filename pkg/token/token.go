@@ -2,10 +2,20 @@ package token
 
 import (
 	"category-service/internal/domain"
+	"context"
 	"time"
 )
 
-type Token interface {
-	GenerateToken(userId uint, expired time.Duration) (string, error)
-	ValidateToken(tokenString string) (*domain.TokenClaims, error)
+// Verifier validates tokens issued by the auth service. It only needs public
+// keys.
+type Verifier interface {
+	// ValidateToken verifies tokenString. ctx bounds any key lookup needed
+	// to verify it.
+	ValidateToken(ctx context.Context, tokenString string) (*domain.TokenClaims, error)
+}
+
+// Signer issues tokens. It needs a private key and is only configured where
+// tokens are minted, such as in local development.
+type Signer interface {
+	GenerateToken(claims domain.TokenClaims, expired time.Duration) (string, error)
 }
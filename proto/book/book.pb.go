@@ -197,6 +197,7 @@ type CategoryData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -245,6 +246,101 @@ func (x *CategoryData) GetName() string {
 	return ""
 }
 
+func (x *CategoryData) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type CategoryBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*CategoryData        `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryBatch) Reset() {
+	*x = CategoryBatch{}
+	mi := &file_proto_book_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryBatch) ProtoMessage() {}
+
+func (x *CategoryBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryBatch.ProtoReflect.Descriptor instead.
+func (*CategoryBatch) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CategoryBatch) GetCategories() []*CategoryData {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type DeleteBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBatch) Reset() {
+	*x = DeleteBatch{}
+	mi := &file_proto_book_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBatch) ProtoMessage() {}
+
+func (x *DeleteBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBatch.ProtoReflect.Descriptor instead.
+func (*DeleteBatch) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteBatch) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
 type BookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -255,7 +351,7 @@ type BookResponse struct {
 
 func (x *BookResponse) Reset() {
 	*x = BookResponse{}
-	mi := &file_proto_book_proto_msgTypes[4]
+	mi := &file_proto_book_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -267,7 +363,7 @@ func (x *BookResponse) String() string {
 func (*BookResponse) ProtoMessage() {}
 
 func (x *BookResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[4]
+	mi := &file_proto_book_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +376,7 @@ func (x *BookResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookResponse.ProtoReflect.Descriptor instead.
 func (*BookResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{4}
+	return file_proto_book_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BookResponse) GetSuccess() bool {
@@ -314,13 +410,20 @@ const file_proto_book_proto_rawDesc = "" +
 	"AuthorData\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
-	"\x03bio\x18\x03 \x01(\tR\x03bio\"2\n" +
+	"\x03bio\x18\x03 \x01(\tR\x03bio\"F\n" +
 	"\fCategoryData\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"B\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"C\n" +
+	"\rCategoryBatch\x122\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x12.book.CategoryDataR\n" +
+	"categories\"\x1f\n" +
+	"\vDeleteBatch\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"B\n" +
 	"\fBookResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xd4\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xcd\x03\n" +
 	"\vBookService\x121\n" +
 	"\vReceiveUser\x12\x0e.book.UserData\x1a\x12.book.BookResponse\x122\n" +
 	"\n" +
@@ -328,7 +431,9 @@ const file_proto_book_proto_rawDesc = "" +
 	"\rReceiveAuthor\x12\x10.book.AuthorData\x1a\x12.book.BookResponse\x124\n" +
 	"\fDeleteAuthor\x12\x10.book.DeleteData\x1a\x12.book.BookResponse\x129\n" +
 	"\x0fReceiveCategory\x12\x12.book.CategoryData\x1a\x12.book.BookResponse\x126\n" +
-	"\x0eDeleteCategory\x12\x10.book.DeleteData\x1a\x12.book.BookResponseB\fZ\n" +
+	"\x0eDeleteCategory\x12\x10.book.DeleteData\x1a\x12.book.BookResponse\x12<\n" +
+	"\x11ReceiveCategories\x12\x13.book.CategoryBatch\x1a\x12.book.BookResponse\x129\n" +
+	"\x10DeleteCategories\x12\x11.book.DeleteBatch\x1a\x12.book.BookResponseB\fZ\n" +
 	"proto/bookb\x06proto3"
 
 var (
@@ -343,32 +448,39 @@ func file_proto_book_proto_rawDescGZIP() []byte {
 	return file_proto_book_proto_rawDescData
 }
 
-var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_book_proto_goTypes = []any{
-	(*UserData)(nil),     // 0: book.UserData
-	(*DeleteData)(nil),   // 1: book.DeleteData
-	(*AuthorData)(nil),   // 2: book.AuthorData
-	(*CategoryData)(nil), // 3: book.CategoryData
-	(*BookResponse)(nil), // 4: book.BookResponse
+	(*UserData)(nil),      // 0: book.UserData
+	(*DeleteData)(nil),    // 1: book.DeleteData
+	(*AuthorData)(nil),    // 2: book.AuthorData
+	(*CategoryData)(nil),  // 3: book.CategoryData
+	(*CategoryBatch)(nil), // 4: book.CategoryBatch
+	(*DeleteBatch)(nil),   // 5: book.DeleteBatch
+	(*BookResponse)(nil),  // 6: book.BookResponse
 }
 var file_proto_book_proto_depIdxs = []int32{
-	0, // 0: book.BookService.ReceiveUser:input_type -> book.UserData
-	1, // 1: book.BookService.DeleteUser:input_type -> book.DeleteData
-	2, // 2: book.BookService.ReceiveAuthor:input_type -> book.AuthorData
-	1, // 3: book.BookService.DeleteAuthor:input_type -> book.DeleteData
-	3, // 4: book.BookService.ReceiveCategory:input_type -> book.CategoryData
-	1, // 5: book.BookService.DeleteCategory:input_type -> book.DeleteData
-	4, // 6: book.BookService.ReceiveUser:output_type -> book.BookResponse
-	4, // 7: book.BookService.DeleteUser:output_type -> book.BookResponse
-	4, // 8: book.BookService.ReceiveAuthor:output_type -> book.BookResponse
-	4, // 9: book.BookService.DeleteAuthor:output_type -> book.BookResponse
-	4, // 10: book.BookService.ReceiveCategory:output_type -> book.BookResponse
-	4, // 11: book.BookService.DeleteCategory:output_type -> book.BookResponse
-	6, // [6:12] is the sub-list for method output_type
-	0, // [0:6] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: book.CategoryBatch.categories:type_name -> book.CategoryData
+	0, // 1: book.BookService.ReceiveUser:input_type -> book.UserData
+	1, // 2: book.BookService.DeleteUser:input_type -> book.DeleteData
+	2, // 3: book.BookService.ReceiveAuthor:input_type -> book.AuthorData
+	1, // 4: book.BookService.DeleteAuthor:input_type -> book.DeleteData
+	3, // 5: book.BookService.ReceiveCategory:input_type -> book.CategoryData
+	1, // 6: book.BookService.DeleteCategory:input_type -> book.DeleteData
+	4, // 7: book.BookService.ReceiveCategories:input_type -> book.CategoryBatch
+	5, // 8: book.BookService.DeleteCategories:input_type -> book.DeleteBatch
+	6, // 9: book.BookService.ReceiveUser:output_type -> book.BookResponse
+	6, // 10: book.BookService.DeleteUser:output_type -> book.BookResponse
+	6, // 11: book.BookService.ReceiveAuthor:output_type -> book.BookResponse
+	6, // 12: book.BookService.DeleteAuthor:output_type -> book.BookResponse
+	6, // 13: book.BookService.ReceiveCategory:output_type -> book.BookResponse
+	6, // 14: book.BookService.DeleteCategory:output_type -> book.BookResponse
+	6, // 15: book.BookService.ReceiveCategories:output_type -> book.BookResponse
+	6, // 16: book.BookService.DeleteCategories:output_type -> book.BookResponse
+	9, // [9:17] is the sub-list for method output_type
+	1, // [1:9] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_proto_book_proto_init() }
@@ -382,7 +494,7 @@ func file_proto_book_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_book_proto_rawDesc), len(file_proto_book_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
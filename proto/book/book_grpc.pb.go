@@ -19,12 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BookService_ReceiveUser_FullMethodName     = "/book.BookService/ReceiveUser"
-	BookService_DeleteUser_FullMethodName      = "/book.BookService/DeleteUser"
-	BookService_ReceiveAuthor_FullMethodName   = "/book.BookService/ReceiveAuthor"
-	BookService_DeleteAuthor_FullMethodName    = "/book.BookService/DeleteAuthor"
-	BookService_ReceiveCategory_FullMethodName = "/book.BookService/ReceiveCategory"
-	BookService_DeleteCategory_FullMethodName  = "/book.BookService/DeleteCategory"
+	BookService_ReceiveUser_FullMethodName       = "/book.BookService/ReceiveUser"
+	BookService_DeleteUser_FullMethodName        = "/book.BookService/DeleteUser"
+	BookService_ReceiveAuthor_FullMethodName     = "/book.BookService/ReceiveAuthor"
+	BookService_DeleteAuthor_FullMethodName      = "/book.BookService/DeleteAuthor"
+	BookService_ReceiveCategory_FullMethodName   = "/book.BookService/ReceiveCategory"
+	BookService_DeleteCategory_FullMethodName    = "/book.BookService/DeleteCategory"
+	BookService_ReceiveCategories_FullMethodName = "/book.BookService/ReceiveCategories"
+	BookService_DeleteCategories_FullMethodName  = "/book.BookService/DeleteCategories"
 )
 
 // BookServiceClient is the client API for BookService service.
@@ -37,6 +39,8 @@ type BookServiceClient interface {
 	DeleteAuthor(ctx context.Context, in *DeleteData, opts ...grpc.CallOption) (*BookResponse, error)
 	ReceiveCategory(ctx context.Context, in *CategoryData, opts ...grpc.CallOption) (*BookResponse, error)
 	DeleteCategory(ctx context.Context, in *DeleteData, opts ...grpc.CallOption) (*BookResponse, error)
+	ReceiveCategories(ctx context.Context, in *CategoryBatch, opts ...grpc.CallOption) (*BookResponse, error)
+	DeleteCategories(ctx context.Context, in *DeleteBatch, opts ...grpc.CallOption) (*BookResponse, error)
 }
 
 type bookServiceClient struct {
@@ -107,6 +111,26 @@ func (c *bookServiceClient) DeleteCategory(ctx context.Context, in *DeleteData,
 	return out, nil
 }
 
+func (c *bookServiceClient) ReceiveCategories(ctx context.Context, in *CategoryBatch, opts ...grpc.CallOption) (*BookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookResponse)
+	err := c.cc.Invoke(ctx, BookService_ReceiveCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteCategories(ctx context.Context, in *DeleteBatch, opts ...grpc.CallOption) (*BookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookResponse)
+	err := c.cc.Invoke(ctx, BookService_DeleteCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BookServiceServer is the server API for BookService service.
 // All implementations must embed UnimplementedBookServiceServer
 // for forward compatibility.
@@ -117,6 +141,8 @@ type BookServiceServer interface {
 	DeleteAuthor(context.Context, *DeleteData) (*BookResponse, error)
 	ReceiveCategory(context.Context, *CategoryData) (*BookResponse, error)
 	DeleteCategory(context.Context, *DeleteData) (*BookResponse, error)
+	ReceiveCategories(context.Context, *CategoryBatch) (*BookResponse, error)
+	DeleteCategories(context.Context, *DeleteBatch) (*BookResponse, error)
 	mustEmbedUnimplementedBookServiceServer()
 }
 
@@ -145,6 +171,12 @@ func (UnimplementedBookServiceServer) ReceiveCategory(context.Context, *Category
 func (UnimplementedBookServiceServer) DeleteCategory(context.Context, *DeleteData) (*BookResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
 }
+func (UnimplementedBookServiceServer) ReceiveCategories(context.Context, *CategoryBatch) (*BookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveCategories not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteCategories(context.Context, *DeleteBatch) (*BookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategories not implemented")
+}
 func (UnimplementedBookServiceServer) mustEmbedUnimplementedBookServiceServer() {}
 func (UnimplementedBookServiceServer) testEmbeddedByValue()                     {}
 
@@ -274,6 +306,42 @@ func _BookService_DeleteCategory_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookService_ReceiveCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReceiveCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReceiveCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReceiveCategories(ctx, req.(*CategoryBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteCategories(ctx, req.(*DeleteBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BookService_ServiceDesc is the grpc.ServiceDesc for BookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +373,14 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCategory",
 			Handler:    _BookService_DeleteCategory_Handler,
 		},
+		{
+			MethodName: "ReceiveCategories",
+			Handler:    _BookService_ReceiveCategories_Handler,
+		},
+		{
+			MethodName: "DeleteCategories",
+			Handler:    _BookService_DeleteCategories_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/book.proto",
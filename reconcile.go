@@ -0,0 +1,69 @@
+package main
+
+import (
+	"category-service/config"
+	"category-service/internal/grpcservice"
+	"category-service/internal/repository"
+	"category-service/pkg/database"
+	"category-service/pkg/logger"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runReconcile implements the "reconcile" subcommand, which queues the
+// categories the Book service is out of date on in the outbox once and prints
+// the report as JSON. The running service delivers them.
+func runReconcile(args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be queued without queuing it")
+	force := flags.Bool("force", false, "push every category, not only those that differ from what the Book service was last sent")
+	batchSize := flags.Int("batch-size", 100, "number of categories read per page")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logger := logger.NewLogger("category-service", logrus.InfoLevel, os.Stderr)
+	logger.SetFormat(cfg.GetLogFormat())
+
+	db := &database.GormDatabase{}
+	if err := db.Connect(cfg); err != nil {
+		logger.Error(fmt.Sprintf("Database connection error: %v", err), "db-error", "connection")
+		return 1
+	}
+	defer db.Close()
+
+	reconciler := grpcservice.NewCategoryReconciler(repository.NewAuthorRepository(db.GetDB()), repository.NewOutboxRepository(db.GetDB()), logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := reconciler.Reconcile(ctx, grpcservice.ReconcileOptions{
+		DryRun:    *dryRun,
+		Force:     *force,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Reconciliation aborted: %v", err), "reconcile", "error")
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Error(fmt.Sprintf("Failed to write report: %v", err), "reconcile", "error")
+		return 1
+	}
+	return 0
+}